@@ -16,20 +16,37 @@
 package main
 
 import (
+	"flag"
 	"os"
 
 	"k8s.io/airflow-operator/pkg/apis"
 	"k8s.io/airflow-operator/pkg/controller"
+	"k8s.io/airflow-operator/pkg/health"
+	"k8s.io/airflow-operator/pkg/metrics"
 	"k8s.io/airflow-operator/pkg/webhook"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
+	"strings"
 	"time"
 )
 
 func main() {
+	var metricsAddr, healthAddr, leaderElectionNamespace, leaderElectionID, namespaces string
+	var enableLeaderElection bool
+	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the prometheus metrics endpoint binds to.")
+	flag.StringVar(&healthAddr, "health-addr", ":8081", "The address the /healthz and /readyz endpoints bind to.")
+	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
+		"Elect a leader among the replicas of the operator, only the leader reconciles.")
+	flag.StringVar(&leaderElectionNamespace, "leader-election-namespace", "",
+		"The namespace of the leader election ConfigMap. Defaults to the namespace of the pod.")
+	flag.StringVar(&leaderElectionID, "leader-election-id", "airflow-operator-leader", "The name of the leader election ConfigMap.")
+	flag.StringVar(&namespaces, "namespaces", "",
+		"The namespace or comma separated namespaces to watch. Defaults to all namespaces.")
+	flag.Parse()
 	logf.SetLogger(logf.ZapLogger(false))
 	log := logf.Log.WithName("entrypoint")
 
@@ -44,27 +61,76 @@ func main() {
 	// Create a new Cmd to provide shared dependencies and start components
 	log.Info("setting up manager")
 	syncperiod := time.Minute * 2
-	mgr, err := manager.New(cfg, manager.Options{SyncPeriod: &syncperiod})
+	watched := watchNamespaces(namespaces)
+	options := manager.Options{
+		SyncPeriod:              &syncperiod,
+		LeaderElection:          enableLeaderElection,
+		LeaderElectionNamespace: leaderElectionNamespace,
+		LeaderElectionID:        leaderElectionID,
+	}
+	if len(watched) != 0 {
+		options.Namespace = watched[0]
+	}
+	mgr, err := manager.New(cfg, options)
 	if err != nil {
 		log.Error(err, "unable to set up overall controller manager")
 		os.Exit(1)
 	}
 
 	log.Info("Registering Components.")
+	if err := addControllers(mgr); err != nil {
+		log.Error(err, "unable to register controllers to the manager")
+		os.Exit(1)
+	}
 
-	// Setup Scheme for all resources
-	log.Info("setting up scheme")
-	if err := apis.AddToScheme(mgr.GetScheme()); err != nil {
-		log.Error(err, "unable add APIs to scheme")
+	// The cache of a manager watches one namespace or all of them. The other
+	// namespaces get a manager each, started by the first one once it leads.
+	clients := []client.Client{mgr.GetClient()}
+	for _, ns := range watched[1:] {
+		log.Info("setting up manager", "namespace", ns)
+		nsmgr, err := manager.New(cfg, manager.Options{SyncPeriod: &syncperiod, Namespace: ns})
+		if err == nil {
+			err = addControllers(nsmgr)
+		}
+		if err == nil {
+			err = mgr.Add(manager.RunnableFunc(nsmgr.Start))
+		}
+		if err != nil {
+			log.Error(err, "unable to set up controller manager", "namespace", ns)
+			os.Exit(1)
+		}
+		clients = append(clients, nsmgr.GetClient())
+	}
+
+	// The metrics and health endpoints are served by every replica, the
+	// runnables of the manager only start on the leader.
+	stop := signals.SetupSignalHandler()
+
+	log.Info("setting up metrics")
+	metrics.RegisterStatusCollector(clients...)
+	if err := mgr.Add(metrics.Leading()); err != nil {
+		log.Error(err, "unable to set up metrics")
 		os.Exit(1)
 	}
+	go func() {
+		if err := (&metrics.Server{Addr: metricsAddr}).Start(stop); err != nil {
+			log.Error(err, "unable to serve metrics")
+			os.Exit(1)
+		}
+	}()
 
-	// Setup all Controllers
-	log.Info("Setting up controller")
-	if err := controller.AddToManager(mgr); err != nil {
-		log.Error(err, "unable to register controllers to the manager")
+	log.Info("setting up health endpoints")
+	healthz := &health.Server{Addr: healthAddr, Config: cfg}
+	if err := mgr.Add(healthz.Leading()); err != nil {
+		log.Error(err, "unable to set up health endpoints")
 		os.Exit(1)
 	}
+	go func() {
+		if err := healthz.Start(stop); err != nil {
+			log.Error(err, "unable to serve health endpoints")
+			os.Exit(1)
+		}
+	}()
 
 	log.Info("setting up webhooks")
 	if err := webhook.AddToManager(mgr); err != nil {
@@ -74,8 +140,27 @@ func main() {
 
 	// Start the Cmd
 	log.Info("Starting the Cmd.")
-	if err := mgr.Start(signals.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(stop); err != nil {
 		log.Error(err, "unable to run the manager")
 		os.Exit(1)
 	}
 }
+
+// watchNamespaces splits the namespaces flag, none means all namespaces
+func watchNamespaces(flag string) []string {
+	namespaces := []string{}
+	for _, ns := range strings.Split(flag, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// addControllers registers the airflow types and controllers with mgr
+func addControllers(mgr manager.Manager) error {
+	if err := apis.AddToScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+	return controller.AddToManager(mgr)
+}
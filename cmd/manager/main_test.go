@@ -0,0 +1,43 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestWatchNamespaces(t *testing.T) {
+	tests := []struct {
+		name string
+		flag string
+		want []string
+	}{
+		{"all namespaces", "", []string{}},
+		{"one", "airflow", []string{"airflow"}},
+		{"several", "airflow,team-a", []string{"airflow", "team-a"}},
+		{"spaces", " airflow , team-a ", []string{"airflow", "team-a"}},
+		{"empty entries", ",airflow,,team-a,", []string{"airflow", "team-a"}},
+		{"only separators", " , ,", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(watchNamespaces(tt.flag)).To(gomega.Equal(tt.want))
+		})
+	}
+}
@@ -20,8 +20,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"net/url"
 	"sigs.k8s.io/controller-reconciler/pkg/finalizer"
 	"sigs.k8s.io/controller-reconciler/pkg/status"
+	"strings"
 )
 
 // defaults and constant strings
@@ -238,6 +240,12 @@ type StorageSpec struct {
 	// Config is generic string based key-value map that defines non-secret configuration values for
 	// uploading the backup to storage w.r.t the configured storage provider.
 	Config map[string]string `json:"config,omitempty"`
+	// Image is the aws-cli image syncing DAGs or plugins from the storage
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Version is the aws-cli image tag
+	// +optional
+	Version string `json:"version,omitempty"`
 }
 
 func (s *StorageSpec) validate(fp *field.Path) field.ErrorList {
@@ -297,11 +305,223 @@ type AirflowUISpec struct {
 	// Resources is the resource requests and limits for the pods.
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Ingress exposes the Airflow UI outside of the cluster
+	// +optional
+	Ingress *IngressSpec `json:"ingress,omitempty"`
+	// Auth enables the role based UI and sets how users log in.
+	// Anyone who can reach the UI can use it when it is not set.
+	// +optional
+	Auth *UIAuthSpec `json:"auth,omitempty"`
+	// TLS serves the UI over https from the pod
+	// +optional
+	TLS *UITLSSpec `json:"tls,omitempty"`
 }
 
 func (s *AirflowUISpec) validate(fp *field.Path) field.ErrorList {
 	errs := field.ErrorList{}
+	if s == nil {
+		return errs
+	}
 	//errs = append(errs, s.Resources.validate(fp.Child("resources"))...)
+	errs = append(errs, s.Ingress.validate(fp.Child("ingress"))...)
+	errs = append(errs, s.Auth.validate(fp.Child("auth"))...)
+	if s.TLS != nil && (s.TLS.SecretName == "") == !s.TLS.SelfSigned {
+		errs = append(errs, field.Invalid(fp.Child("tls"), s.TLS, "exactly one of secretName and selfSigned must be set"))
+	}
+	return errs
+}
+
+// BaseURL returns the url users reach the UI on
+func (s *AirflowUISpec) BaseURL() string {
+	if s.Ingress != nil {
+		return s.Ingress.URL()
+	}
+	if s.TLS != nil {
+		return "https://localhost:8080"
+	}
+	return "http://localhost:8080"
+}
+
+// UITLSSpec defines the certificate the UI serves https with
+type UITLSSpec struct {
+	// SecretName is a kubernetes.io/tls secret with the tls.crt and tls.key of the UI
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// SelfSigned has the operator generate a self signed certificate for the
+	// UI Service and Ingress host. It is renewed before it expires.
+	// +optional
+	SelfSigned bool `json:"selfSigned,omitempty"`
+}
+
+// IngressSpec defines the Ingress routing a host to a component Service
+type IngressSpec struct {
+	// Host is the DNS name the component is served on
+	Host string `json:"host,omitempty"`
+	// TLSSecret is the secret holding the certificate for Host. The
+	// component is served over plain http when it is not set.
+	// +optional
+	TLSSecret string `json:"tlsSecret,omitempty"`
+	// Annotations are added to the Ingress e.g. to select the ingress class
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+func (s *IngressSpec) validate(fp *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if s == nil {
+		return errs
+	}
+	if s.Host == "" {
+		errs = append(errs, field.Required(fp.Child("host"), "host missing"))
+	}
+	return errs
+}
+
+// URL returns the url the Ingress serves the component on
+func (s *IngressSpec) URL() string {
+	if s.TLSSecret != "" {
+		return "https://" + s.Host
+	}
+	return "http://" + s.Host
+}
+
+// UIAuthSpec defines how users log into the Airflow UI. Exactly one of
+// Password, LDAP and OAuth is set.
+type UIAuthSpec struct {
+	// Password keeps the users in the metadata DB
+	// +optional
+	Password *PasswordAuthSpec `json:"password,omitempty"`
+	// LDAP checks the users against an LDAP server
+	// +optional
+	LDAP *LDAPAuthSpec `json:"ldap,omitempty"`
+	// OAuth logs users in through an OAuth2/OpenID Connect provider
+	// +optional
+	OAuth *OAuthSpec `json:"oauth,omitempty"`
+	// UserRegistrationRole is the role LDAP and OAuth users get on their first login. Defaults to Viewer
+	// +optional
+	UserRegistrationRole string `json:"userRegistrationRole,omitempty"`
+}
+
+// PasswordAuthSpec defines the password login
+type PasswordAuthSpec struct {
+	// AdminSecretRef is a secret with the username, password and email keys
+	// of the Admin user created before the UI starts. Existing users are
+	// left as they are.
+	AdminSecretRef *corev1.LocalObjectReference `json:"adminSecretRef,omitempty"`
+}
+
+// LDAPAuthSpec defines the LDAP login
+type LDAPAuthSpec struct {
+	// Server is the url of the LDAP server e.g. ldap://ldap.example.com:389
+	Server string `json:"server,omitempty"`
+	// SearchBase is the DN users are searched under e.g. ou=people,dc=example,dc=com
+	SearchBase string `json:"searchBase,omitempty"`
+	// UIDField is the attribute matched against the login name. Defaults to uid
+	// +optional
+	UIDField string `json:"uidField,omitempty"`
+	// BindUser is the DN to search as. The search is anonymous when it is not set
+	// +optional
+	BindUser string `json:"bindUser,omitempty"`
+	// BindSecretRef is the secret key holding the password of BindUser
+	// +optional
+	BindSecretRef *corev1.SecretKeySelector `json:"bindSecretRef,omitempty"`
+	// UseTLS upgrades an ldap:// connection with StartTLS
+	// +optional
+	UseTLS bool `json:"useTLS,omitempty"`
+	// AllowSelfSigned accepts server certificates that are not signed by a known CA
+	// +optional
+	AllowSelfSigned bool `json:"allowSelfSigned,omitempty"`
+}
+
+// OAuthSpec defines the login through a generic OAuth2/OpenID Connect provider
+type OAuthSpec struct {
+	// Name of the provider on the login page. Defaults to oidc
+	// +optional
+	Name string `json:"name,omitempty"`
+	// AuthorizeURL is the authorization endpoint the browser is sent to
+	AuthorizeURL string `json:"authorizeURL,omitempty"`
+	// TokenURL is the token endpoint the UI exchanges the code at
+	TokenURL string `json:"tokenURL,omitempty"`
+	// UserInfoURL is the endpoint returning the claims of the user
+	UserInfoURL string `json:"userInfoURL,omitempty"`
+	// Scopes requested from the provider. Defaults to openid, email and profile
+	// +optional
+	Scopes []string `json:"scopes,omitempty"`
+	// ClientSecretRef is a secret with the client-id and client-secret keys
+	ClientSecretRef *corev1.LocalObjectReference `json:"clientSecretRef,omitempty"`
+}
+
+func (s *UIAuthSpec) applyDefaults() {
+	if s == nil {
+		return
+	}
+	if s.UserRegistrationRole == "" {
+		s.UserRegistrationRole = "Viewer"
+	}
+	if s.LDAP != nil && s.LDAP.UIDField == "" {
+		s.LDAP.UIDField = "uid"
+	}
+	if s.OAuth != nil {
+		if s.OAuth.Name == "" {
+			s.OAuth.Name = "oidc"
+		}
+		if len(s.OAuth.Scopes) == 0 {
+			s.OAuth.Scopes = []string{"openid", "email", "profile"}
+		}
+	}
+}
+
+func (s *UIAuthSpec) validate(fp *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if s == nil {
+		return errs
+	}
+	modes := 0
+	if s.Password != nil {
+		modes++
+		if s.Password.AdminSecretRef == nil || s.Password.AdminSecretRef.Name == "" {
+			errs = append(errs, field.Required(fp.Child("password", "adminSecretRef", "name"), "admin secret missing"))
+		}
+	}
+	if l := s.LDAP; l != nil {
+		modes++
+		lp := fp.Child("ldap")
+		if !strings.HasPrefix(l.Server, "ldap://") && !strings.HasPrefix(l.Server, "ldaps://") {
+			errs = append(errs, field.Invalid(lp.Child("server"), l.Server, "must be an ldap:// or ldaps:// url"))
+		}
+		if l.SearchBase == "" {
+			errs = append(errs, field.Required(lp.Child("searchBase"), "search base missing"))
+		}
+		if l.BindSecretRef != nil {
+			if l.BindUser == "" {
+				errs = append(errs, field.Required(lp.Child("bindUser"), "bind user missing for the bind secret"))
+			}
+			if l.BindSecretRef.Name == "" || l.BindSecretRef.Key == "" {
+				errs = append(errs, field.Required(lp.Child("bindSecretRef"), "name and key required"))
+			}
+		}
+	}
+	if o := s.OAuth; o != nil {
+		modes++
+		op := fp.Child("oauth")
+		urls := []struct{ name, value string }{
+			{"authorizeURL", o.AuthorizeURL},
+			{"tokenURL", o.TokenURL},
+			{"userInfoURL", o.UserInfoURL},
+		}
+		for _, u := range urls {
+			parsed, err := url.Parse(u.value)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				errs = append(errs, field.Invalid(op.Child(u.name), u.value, "must be an http(s) url"))
+			}
+		}
+		if o.ClientSecretRef == nil || o.ClientSecretRef.Name == "" {
+			errs = append(errs, field.Required(op.Child("clientSecretRef", "name"), "client secret missing"))
+		}
+	}
+	if modes != 1 {
+		errs = append(errs, field.Invalid(fp, modes, "exactly one of password, ldap and oauth must be set"))
+	}
 	return errs
 }
 
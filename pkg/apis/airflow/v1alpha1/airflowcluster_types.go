@@ -22,6 +22,8 @@ import (
 	"math/rand"
 	"sigs.k8s.io/controller-reconciler/pkg/finalizer"
 	"sigs.k8s.io/controller-reconciler/pkg/status"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -39,14 +41,29 @@ const (
 	GitsyncVersion          = "v3.0.1"
 	GCSsyncImage            = "gcr.io/cloud-airflow-releaser/gcs-syncd"
 	GCSsyncVersion          = "cloud_composer_service_2018-05-23-RC0"
+	GCSfetchImage           = "google/cloud-sdk"
+	GCSfetchVersion         = "237.0.0-alpine"
+	S3syncImage             = "mesosphere/aws-cli"
+	S3syncVersion           = "1.14.5"
 	ExecutorLocal           = "Local"
 	ExecutorCelery          = "Celery"
 	ExecutorSequential      = "Sequential"
 	ExecutorK8s             = "Kubernetes"
+	LogProviderGCS          = "gcs"
 	defaultExecutor         = ExecutorLocal
 	defaultBranch           = "master"
+	defaultDagCheckInterval = 300
+	defaultLogRetentionDays = 30
+	defaultLogRetentionCron = "0 3 * * *"
 	defaultWorkerVersion    = "1.10.2"
+	LabelSecretCluster      = "airflow.k8s.io/cluster"
+	LabelSecretConnection   = "airflow.k8s.io/connection"
+	LabelSecretVariable     = "airflow.k8s.io/variable"
 	defaultSchedulerVersion = "1.10.2"
+	defaultStatsdImage      = "prom/statsd-exporter"
+	defaultStatsdVersion    = "v0.12.2"
+	defaultSchedLiveness    = 300
+	defaultSchedReadiness   = 60
 )
 
 var (
@@ -187,10 +204,17 @@ type FlowerSpec struct {
 	// Resources is the resource requests and limits for the pods.
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Ingress exposes Flower outside of the cluster
+	// +optional
+	Ingress *IngressSpec `json:"ingress,omitempty"`
 }
 
 func (s *FlowerSpec) validate(fp *field.Path) field.ErrorList {
 	errs := field.ErrorList{}
+	if s == nil {
+		return errs
+	}
+	errs = append(errs, s.Ingress.validate(fp.Child("ingress"))...)
 	return errs
 }
 
@@ -210,10 +234,28 @@ type SchedulerSpec struct {
 	DBUser string `json:"dbuser,omitempty"`
 	// Resources is the resource requests and limits for the pods.
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// LivenessThreshold is the age in seconds of the last heartbeat of the
+	// scheduler job after which the scheduler is restarted. Defaults to 300.
+	// +optional
+	LivenessThreshold int32 `json:"livenessThreshold,omitempty"`
+	// ReadinessThreshold is the age in seconds of the last heartbeat of the
+	// scheduler job after which the scheduler is not ready. Defaults to 60.
+	// +optional
+	ReadinessThreshold int32 `json:"readinessThreshold,omitempty"`
 }
 
 func (s *SchedulerSpec) validate(fp *field.Path) field.ErrorList {
-	return field.ErrorList{}
+	errs := field.ErrorList{}
+	if s == nil {
+		return errs
+	}
+	if s.LivenessThreshold < 0 {
+		errs = append(errs, field.Invalid(fp.Child("livenessThreshold"), s.LivenessThreshold, "should not be negative"))
+	}
+	if s.ReadinessThreshold < 0 {
+		errs = append(errs, field.Invalid(fp.Child("readinessThreshold"), s.ReadinessThreshold, "should not be negative"))
+	}
+	return errs
 }
 
 // WorkerSpec defines the attributes and desired state of Airflow workers
@@ -240,6 +282,15 @@ type GCSSpec struct {
 	Bucket string `json:"bucket,omitempty"`
 	// Once syncs initially and quits (use init container instead of sidecar)
 	Once bool `json:"once,omitempty"`
+	// Prefix is a versioned prefix in the bucket that is fetched in once mode
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+	// Object is a tar.gz bundle of DAGs that is fetched and extracted in once mode
+	// +optional
+	Object string `json:"object,omitempty"`
+	// Generation pins the Object to a specific GCS object generation
+	// +optional
+	Generation int64 `json:"generation,omitempty"`
 }
 
 func (s *GCSSpec) validate(fp *field.Path) field.ErrorList {
@@ -250,12 +301,37 @@ func (s *GCSSpec) validate(fp *field.Path) field.ErrorList {
 	if s.Bucket == "" {
 		errs = append(errs, field.Required(fp.Child("bucket"), "bucket required"))
 	}
-	if s.Once == true {
-		errs = append(errs, field.NotSupported(fp.Child("once"), "true", []string{}))
+	if !s.Once {
+		if s.Prefix != "" {
+			errs = append(errs, field.Invalid(fp.Child("prefix"), s.Prefix, "prefix is supported only with once"))
+		}
+		if s.Object != "" {
+			errs = append(errs, field.Invalid(fp.Child("object"), s.Object, "object is supported only with once"))
+		}
+	}
+	if s.Prefix != "" && s.Object != "" {
+		errs = append(errs, field.Invalid(fp.Child("object"), s.Object, "only one of prefix and object can be set"))
+	}
+	if s.Once && s.Prefix == "" && s.Object == "" {
+		errs = append(errs, field.Required(fp.Child("prefix"), "once requires a versioned prefix or an object and generation"))
+	}
+	if s.Object != "" && s.Generation == 0 {
+		errs = append(errs, field.Required(fp.Child("generation"), "object must be pinned to a generation"))
+	}
+	if s.Object == "" && s.Generation != 0 {
+		errs = append(errs, field.Required(fp.Child("object"), "generation requires an object"))
 	}
 	return errs
 }
 
+// Source returns the gsutil url of the pinned DAG source
+func (s *GCSSpec) Source() string {
+	if s.Object != "" {
+		return "gs://" + s.Bucket + "/" + strings.TrimPrefix(s.Object, "/") + "#" + strconv.FormatInt(s.Generation, 10)
+	}
+	return "gs://" + s.Bucket + "/" + strings.TrimPrefix(s.Prefix, "/")
+}
+
 //GitSpec defines the atributed needed to sync from a git repo
 type GitSpec struct {
 	// Repo describes the http/ssh uri for git repo
@@ -270,6 +346,24 @@ type GitSpec struct {
 	Once bool `json:"once,omitempty"`
 	// Reference to git credentials (user, password, ssh etc)
 	CredSecretRef *corev1.LocalObjectReference `json:"cred,omitempty"`
+	// Reference to a secret with the ssh deploy key in field gitSshKey
+	// and the server host keys in field known_hosts
+	// +optional
+	SSHSecretRef *corev1.LocalObjectReference `json:"sshSecret,omitempty"`
+	// Validation gates new commits on Branch behind a DAG import check.
+	// Components run the last validated revision instead of the branch head.
+	// +optional
+	Validation *DagValidationSpec `json:"validation,omitempty"`
+}
+
+// DagValidationSpec defines how new DAG revisions are validated
+type DagValidationSpec struct {
+	// Interval is the number of seconds between checks for new commits
+	// +optional
+	Interval int32 `json:"interval,omitempty"`
+	// Resources is the resource requests and limits for the validation job
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 func (s *GitSpec) validate(fp *field.Path) field.ErrorList {
@@ -284,6 +378,22 @@ func (s *GitSpec) validate(fp *field.Path) field.ErrorList {
 	if s.CredSecretRef != nil && s.CredSecretRef.Name == "" {
 		errs = append(errs, field.Required(fp.Child("cred", "name"), "name missing"))
 	}
+	if s.SSHSecretRef != nil {
+		if s.SSHSecretRef.Name == "" {
+			errs = append(errs, field.Required(fp.Child("sshSecret", "name"), "name missing"))
+		}
+		if s.CredSecretRef != nil {
+			errs = append(errs, field.Invalid(fp.Child("sshSecret"), s.SSHSecretRef.Name, "only one of cred and sshSecret can be set"))
+		}
+	}
+	if s.Validation != nil {
+		if s.Rev != "" {
+			errs = append(errs, field.Invalid(fp.Child("rev"), s.Rev, "rev is managed by the operator when validation is enabled"))
+		}
+		if s.Validation.Interval < 0 {
+			errs = append(errs, field.Invalid(fp.Child("validation", "interval"), s.Validation.Interval, "must not be negative"))
+		}
+	}
 	//errs = append(errs, field.NotSupported(fp.Child("cred"), "", []string{}))
 	return errs
 }
@@ -297,7 +407,9 @@ type DagSpec struct {
 	Git *GitSpec `json:"git,omitempty"`
 	// NfsPVSpec
 	NfsPV *corev1.PersistentVolumeClaim `json:"nfspv,omitempty"`
-	// Storage has s3 compatible storage spec for copying files from
+	// Storage has s3 compatible storage spec for copying files from.
+	// Config keys endpoint, region and bucket are required, prefix and
+	// interval (seconds between syncs) are optional.
 	Storage *StorageSpec `json:"storage,omitempty"`
 	// Gcs config which uses storage spec
 	GCS *GCSSpec `json:"gcs,omitempty"`
@@ -312,10 +424,257 @@ func (s *DagSpec) validate(fp *field.Path) field.ErrorList {
 		errs = append(errs, field.NotSupported(fp.Child("nfspv"), "", []string{}))
 	}
 	if s.Storage != nil {
-		errs = append(errs, field.NotSupported(fp.Child("storage"), "", []string{}))
+		errs = append(errs, s.Storage.validate(fp.Child("storage"))...)
+	}
+	errs = append(errs, s.Git.validate(fp.Child("git"))...)
+	errs = append(errs, s.GCS.validate(fp.Child("gcs"))...)
+	return errs
+}
+
+// PluginsSpec defines where the plugins are located and the extra python
+// packages needed by the DAGs and plugins
+type PluginsSpec struct {
+	// PluginsSubdir is the directory under source where the plugins are present
+	// +optional
+	PluginsSubdir string `json:"subdir,omitempty"`
+	// GitSpec defines details to pull plugins from a git repo
+	// +optional
+	Git *GitSpec `json:"git,omitempty"`
+	// Storage has s3 compatible storage spec for copying plugins from
+	// +optional
+	Storage *StorageSpec `json:"storage,omitempty"`
+	// Gcs config for copying plugins from
+	// +optional
+	GCS *GCSSpec `json:"gcs,omitempty"`
+	// Requirements is a list of pip requirement specifiers installed into
+	// a shared volume on PYTHONPATH before the airflow containers start
+	// +optional
+	Requirements []string `json:"requirements,omitempty"`
+}
+
+func (s *PluginsSpec) validate(fp *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if s == nil {
+		return errs
+	}
+	sources := 0
+	if s.Git != nil {
+		sources++
+		if s.Git.Validation != nil {
+			errs = append(errs, field.Invalid(fp.Child("git", "validation"), "", "validation is supported only for dags"))
+		}
+	}
+	if s.GCS != nil {
+		sources++
+	}
+	if s.Storage != nil {
+		sources++
+		errs = append(errs, s.Storage.validate(fp.Child("storage"))...)
+	}
+	if sources > 1 {
+		errs = append(errs, field.Invalid(fp, "", "only one of git, gcs and storage can be set"))
 	}
 	errs = append(errs, s.Git.validate(fp.Child("git"))...)
-	errs = append(errs, s.GCS.validate(fp.Child("git"))...)
+	errs = append(errs, s.GCS.validate(fp.Child("gcs"))...)
+	for i, req := range s.Requirements {
+		if strings.TrimSpace(req) == "" || strings.HasPrefix(strings.TrimSpace(req), "-") {
+			errs = append(errs, field.Invalid(fp.Child("requirements").Index(i), req, "must be a pip requirement specifier"))
+		}
+	}
+	return errs
+}
+
+// LoggingSpec defines where the task logs are uploaded to
+type LoggingSpec struct {
+	// Storage is the s3 compatible storage or the GCS bucket for the logs.
+	// Provider s3 uses config keys endpoint, region, bucket and prefix and
+	// the secret fields AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+	// Provider gcs uses config keys bucket and prefix and the service
+	// account key in secret field key.json.
+	Storage StorageSpec `json:"storage"`
+}
+
+func (s *LoggingSpec) validate(fp *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if s == nil {
+		return errs
+	}
+	if s.Storage.StorageProvider != LogProviderGCS {
+		return append(errs, s.Storage.validate(fp.Child("storage"))...)
+	}
+	if s.Storage.SecretRef == nil {
+		errs = append(errs, field.Required(fp.Child("storage", "secretRef"), ""))
+	} else if s.Storage.SecretRef.Name == "" {
+		errs = append(errs, field.Required(fp.Child("storage", "secretRef", "name"), ""))
+	}
+	if s.Storage.Config["bucket"] == "" {
+		errs = append(errs, field.Required(fp.Child("storage", "config").Key("bucket"), "no storage config 'bucket'"))
+	}
+	return errs
+}
+
+// RemoteBaseFolder returns the url the task logs are uploaded to
+func (s *LoggingSpec) RemoteBaseFolder() string {
+	scheme := "s3://"
+	if s.Storage.StorageProvider == LogProviderGCS {
+		scheme = "gs://"
+	}
+	return scheme + s.Storage.Config["bucket"] + "/" + strings.TrimPrefix(s.Storage.Config["prefix"], "/")
+}
+
+// LogVolumeSpec defines a shared volume for the logs folder
+type LogVolumeSpec struct {
+	// Claim is the template for a ReadWriteMany PVC created for the logs
+	// +optional
+	Claim *corev1.PersistentVolumeClaim `json:"claim,omitempty"`
+	// NFS uses an export on the NFS server of the AirflowBase
+	// +optional
+	NFS bool `json:"nfs,omitempty"`
+	// RetentionDays is the age in days after which log files are deleted
+	// +optional
+	RetentionDays int32 `json:"retentionDays,omitempty"`
+	// Schedule is the cron schedule of the retention job
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+}
+
+func (s *LogVolumeSpec) validate(fp *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if s == nil {
+		return errs
+	}
+	if s.Claim == nil && !s.NFS {
+		errs = append(errs, field.Required(fp, "one of claim and nfs is required"))
+	}
+	if s.Claim != nil && s.NFS {
+		errs = append(errs, field.Invalid(fp.Child("nfs"), s.NFS, "only one of claim and nfs can be set"))
+	}
+	if s.Claim != nil {
+		rwx := false
+		for _, mode := range s.Claim.Spec.AccessModes {
+			if mode == corev1.ReadWriteMany {
+				rwx = true
+			}
+		}
+		if !rwx {
+			errs = append(errs, field.Invalid(fp.Child("claim", "spec", "accessModes"), s.Claim.Spec.AccessModes, "ReadWriteMany is required to share the logs"))
+		}
+	}
+	if s.RetentionDays < 0 {
+		errs = append(errs, field.Invalid(fp.Child("retentionDays"), s.RetentionDays, "must not be negative"))
+	}
+	if !validCronString(s.Schedule) {
+		errs = append(errs, field.Invalid(fp.Child("schedule"), s.Schedule, "Invalid Schedule cron string"))
+	}
+	return errs
+}
+
+// SecretsBackendSpec configures Airflow to read connections and variables
+// from labeled Secrets in the namespace of the cluster. Secrets backends
+// need Airflow 1.10.10 or later.
+type SecretsBackendSpec struct {
+	// Selector are the labels the secrets must carry. Defaults to the cluster label
+	// +optional
+	Selector map[string]string `json:"selector,omitempty"`
+}
+
+// secretsBackendMinVersion is the first Airflow release with airflow.secrets
+var secretsBackendMinVersion = []int{1, 10, 10}
+
+// gitSyncRevMinVersion is the first Airflow release passing git_sync_rev to task pods
+var gitSyncRevMinVersion = []int{1, 10, 10}
+
+func (s *SecretsBackendSpec) validate(fp *field.Path, cluster *AirflowClusterSpec) field.ErrorList {
+	errs := field.ErrorList{}
+	if s == nil {
+		return errs
+	}
+	versions := [][2]string{}
+	if cluster.Scheduler != nil {
+		versions = append(versions, [2]string{"scheduler", cluster.Scheduler.Version})
+	}
+	if cluster.Worker != nil {
+		versions = append(versions, [2]string{"worker", cluster.Worker.Version})
+	}
+	if cluster.UI != nil {
+		versions = append(versions, [2]string{"ui", cluster.UI.Version})
+	}
+	for _, v := range versions {
+		if versionBefore(v[1], secretsBackendMinVersion) {
+			errs = append(errs, field.Invalid(fp, "", v[0]+" runs airflow "+v[1]+", secrets backends need 1.10.10 or later"))
+		}
+	}
+	for _, label := range []string{LabelSecretConnection, LabelSecretVariable} {
+		if _, ok := s.Selector[label]; ok {
+			errs = append(errs, field.Invalid(fp.Child("selector").Key(label), s.Selector[label], "names the connection or variable of a secret"))
+		}
+	}
+	return errs
+}
+
+// versionBefore returns true if the numeric release in version e.g. 1.10.2 or
+// 2.1.0-python3.8 is older than min. Versions that do not start with a
+// number e.g. latest are assumed to be recent.
+func versionBefore(version string, min []int) bool {
+	release := strings.SplitN(version, "-", 2)[0]
+	parts := strings.Split(release, ".")
+	for i, m := range min {
+		if i >= len(parts) {
+			return false
+		}
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return false
+		}
+		if n != m {
+			return n < m
+		}
+	}
+	return false
+}
+
+// MetricsSpec turns on the StatsD metrics of the Airflow components and
+// deploys a statsd-exporter that serves them to prometheus
+type MetricsSpec struct {
+	// Image defines the statsd-exporter Docker image.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Version defines the statsd-exporter Docker image version.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Resources is the resource requests and limits for the exporter pod.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Mappings is the mapping config of the statsd-exporter. Defaults to
+	// mappings that turn the dag, task, operator and pool names in the
+	// Airflow metric names into labels
+	// +optional
+	Mappings string `json:"mappings,omitempty"`
+	// ServiceMonitor creates a prometheus-operator ServiceMonitor for the exporter
+	// +optional
+	ServiceMonitor *ServiceMonitorSpec `json:"serviceMonitor,omitempty"`
+}
+
+// ServiceMonitorSpec defines the ServiceMonitor of the statsd-exporter
+type ServiceMonitorSpec struct {
+	// Interval between scrapes e.g. 30s. Defaults to the interval of prometheus
+	// +optional
+	Interval string `json:"interval,omitempty"`
+	// Labels are added to the ServiceMonitor to match the serviceMonitorSelector of prometheus
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+func (s *MetricsSpec) validate(fp *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if s == nil {
+		return errs
+	}
+	if sm := s.ServiceMonitor; sm != nil && sm.Interval != "" {
+		if _, err := time.ParseDuration(sm.Interval); err != nil {
+			errs = append(errs, field.Invalid(fp.Child("serviceMonitor", "interval"), sm.Interval, err.Error()))
+		}
+	}
 	return errs
 }
 
@@ -334,6 +693,10 @@ type ClusterConfig struct {
 	// AirflowSecret defines a list of secret envs
 	// +optional
 	AirflowSecretEnv []SecretEnv `json:"airflowsecret,omitempty"`
+	// Sections has airflow.cfg keys per section. They are merged with the
+	// operator defaults into the airflow.cfg mounted into all components.
+	// +optional
+	Sections map[string]map[string]string `json:"sections,omitempty"`
 }
 
 // AirflowClusterSpec defines the desired state of AirflowCluster
@@ -378,6 +741,24 @@ type AirflowClusterSpec struct {
 	// Spec for DAG source and location
 	// +optional
 	DAGs *DagSpec `json:"dags,omitempty"`
+	// Spec for plugins source and python requirements
+	// +optional
+	Plugins *PluginsSpec `json:"plugins,omitempty"`
+	// Spec for remote task logging
+	// +optional
+	Logging *LoggingSpec `json:"logging,omitempty"`
+	// Spec for a shared volume mounted at the logs folder
+	// +optional
+	LogVolume *LogVolumeSpec `json:"logVolume,omitempty"`
+	// Spec for reading connections and variables from Secrets
+	// +optional
+	SecretsBackend *SecretsBackendSpec `json:"secretsBackend,omitempty"`
+	// Spec for the StatsD metrics of the Airflow components
+	// +optional
+	Metrics *MetricsSpec `json:"metrics,omitempty"`
+	// FernetKeyRotation rotates the Fernet key and re-encrypts the metadata DB whenever it changes
+	// +optional
+	FernetKeyRotation string `json:"fernetKeyRotation,omitempty"`
 	// AirflowBaseRef is a reference to the AirflowBase CR
 	AirflowBaseRef *corev1.LocalObjectReference `json:"airflowbase,omitempty"`
 }
@@ -386,8 +767,24 @@ type AirflowClusterSpec struct {
 type SchedulerStatus struct {
 	// DagCount is a count of number of Dags observed
 	DagCount int32 `json:"dagcount,omitempty"`
+	// PausedDagCount is the number of paused Dags
+	PausedDagCount int32 `json:"pausedDagCount,omitempty"`
 	// RunCount is a count of number of Dag Runs observed
 	RunCount int32 `json:"runcount,omitempty"`
+	// RunningRunCount is the number of running Dag Runs
+	RunningRunCount int32 `json:"runningRunCount,omitempty"`
+	// QueuedRunCount is the number of queued Dag Runs
+	QueuedRunCount int32 `json:"queuedRunCount,omitempty"`
+	// FailedTaskCount is the number of task instances that failed in the last hour
+	FailedTaskCount int32 `json:"failedTaskCount,omitempty"`
+	// LastHeartbeatTime is the last heartbeat of the scheduler job
+	// +optional
+	LastHeartbeatTime *metav1.Time `json:"lastHeartbeatTime,omitempty"`
+	// HeartbeatAge is the age in seconds of the last heartbeat at LastCheckTime
+	HeartbeatAge int64 `json:"heartbeatAge,omitempty"`
+	// LastCheckTime is when the metadata DB was last queried successfully
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
 }
 
 // MemoryStoreStatus defines the observed state of MemoryStore
@@ -411,10 +808,73 @@ type MemoryStoreStatus struct {
 	status.ComponentMeta `json:",inline"`
 }
 
+// ComponentDagStatus defines the DAG state observed in the pods of a component
+type ComponentDagStatus struct {
+	// Revision is the git commit or GCS generation synced by the component
+	Revision string `json:"revision,omitempty"`
+	// DagCount is a count of the DAG files in the synced DAG folder
+	DagCount int32 `json:"dagcount,omitempty"`
+	// LastSyncTime is when the synced DAG folder last changed
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// DagStatus defines the observed state of the DAG source
+type DagStatus struct {
+	// Source is the location the DAGs were fetched from
+	Source string `json:"source,omitempty"`
+	// Revision is the version of the source running in all components
+	Revision string `json:"revision,omitempty"`
+	// Components is the DAG state observed per component
+	Components map[string]ComponentDagStatus `json:"components,omitempty"`
+	// LastCheckTime is when the components were last inspected
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+}
+
+// DagValidationStatus defines the state of the DAG validation gate
+type DagValidationStatus struct {
+	// Revision is the last revision that passed validation, components run it
+	Revision string `json:"revision,omitempty"`
+	// Candidate is the branch head checked by the last validation job
+	Candidate string `json:"candidate,omitempty"`
+	// LastCheckTime is when the last validation job was started
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+}
+
+// FernetKeyStatus is the status of the Fernet key rotation
+type FernetKeyStatus struct {
+	// Rotation is the value of FernetKeyRotation the key was last rotated for
+	// +optional
+	Rotation string `json:"rotation,omitempty"`
+	// Pending is the value of FernetKeyRotation of the rotation in progress
+	// +optional
+	Pending string `json:"pending,omitempty"`
+	// LastRotationTime is when the metadata DB was last re-encrypted
+	// +optional
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+}
+
 // AirflowClusterStatus defines the observed state of AirflowCluster
 type AirflowClusterStatus struct {
 	status.Meta          `json:",inline"`
 	status.ComponentMeta `json:",inline"`
+	// DAGs is the status of the DAG source deployed in the cluster
+	// +optional
+	DAGs *DagStatus `json:"dags,omitempty"`
+	// Scheduler is the status of the Airflow Scheduler component
+	// +optional
+	Scheduler *SchedulerStatus `json:"scheduler,omitempty"`
+	// DagValidation is the status of the DAG validation gate
+	// +optional
+	DagValidation *DagValidationStatus `json:"dagValidation,omitempty"`
+	// FernetKey is the status of the Fernet key rotation
+	// +optional
+	FernetKey *FernetKeyStatus `json:"fernetKey,omitempty"`
+	// UIURL is the url the Airflow UI is served on
+	// +optional
+	UIURL string `json:"uiURL,omitempty"`
+	// FlowerURL is the url Flower is served on
+	// +optional
+	FlowerURL string `json:"flowerURL,omitempty"`
 }
 
 // +genclient
@@ -469,6 +929,12 @@ func (b *AirflowCluster) ApplyDefaults() {
 		if b.Spec.Scheduler.DBUser == "" {
 			b.Spec.Scheduler.DBUser = string(RandomAlphanumericString(16))
 		}
+		if b.Spec.Scheduler.LivenessThreshold == 0 {
+			b.Spec.Scheduler.LivenessThreshold = defaultSchedLiveness
+		}
+		if b.Spec.Scheduler.ReadinessThreshold == 0 {
+			b.Spec.Scheduler.ReadinessThreshold = defaultSchedReadiness
+		}
 	}
 	if b.Spec.UI != nil {
 		if b.Spec.UI.Image == "" {
@@ -480,6 +946,7 @@ func (b *AirflowCluster) ApplyDefaults() {
 		if b.Spec.UI.Replicas == 0 {
 			b.Spec.UI.Replicas = 1
 		}
+		b.Spec.UI.Auth.applyDefaults()
 	}
 	if b.Spec.Flower != nil {
 		if b.Spec.Flower.Image == "" {
@@ -514,12 +981,64 @@ func (b *AirflowCluster) ApplyDefaults() {
 			if b.Spec.DAGs.Git.Branch == "" {
 				b.Spec.DAGs.Git.Branch = defaultBranch
 			}
+			if b.Spec.DAGs.Git.Validation != nil && b.Spec.DAGs.Git.Validation.Interval == 0 {
+				b.Spec.DAGs.Git.Validation.Interval = defaultDagCheckInterval
+			}
 		}
+		if b.Spec.DAGs.Storage != nil {
+			b.Spec.DAGs.Storage.applySyncDefaults()
+		}
+	}
+	if b.Spec.Plugins != nil {
+		if b.Spec.Plugins.Git != nil && b.Spec.Plugins.Git.Branch == "" {
+			b.Spec.Plugins.Git.Branch = defaultBranch
+		}
+		if b.Spec.Plugins.Storage != nil {
+			b.Spec.Plugins.Storage.applySyncDefaults()
+		}
+	}
+	if b.Spec.Logging != nil && b.Spec.Logging.Storage.StorageProvider == "" {
+		b.Spec.Logging.Storage.StorageProvider = defaultStorageProvider
+	}
+	if b.Spec.LogVolume != nil {
+		if b.Spec.LogVolume.Claim != nil && len(b.Spec.LogVolume.Claim.Spec.AccessModes) == 0 {
+			b.Spec.LogVolume.Claim.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}
+		}
+		if b.Spec.LogVolume.RetentionDays == 0 {
+			b.Spec.LogVolume.RetentionDays = defaultLogRetentionDays
+		}
+		if b.Spec.LogVolume.Schedule == "" {
+			b.Spec.LogVolume.Schedule = defaultLogRetentionCron
+		}
+	}
+	if b.Spec.Metrics != nil {
+		if b.Spec.Metrics.Image == "" {
+			b.Spec.Metrics.Image = defaultStatsdImage
+		}
+		if b.Spec.Metrics.Version == "" {
+			b.Spec.Metrics.Version = defaultStatsdVersion
+		}
+	}
+	if b.Spec.SecretsBackend != nil && len(b.Spec.SecretsBackend.Selector) == 0 {
+		b.Spec.SecretsBackend.Selector = map[string]string{LabelSecretCluster: b.Name}
 	}
 	b.Status.ComponentList = status.ComponentList{}
 	finalizer.EnsureStandard(b)
 }
 
+// applySyncDefaults defaults the storage DAGs or plugins are synced from
+func (s *StorageSpec) applySyncDefaults() {
+	if s.StorageProvider == "" {
+		s.StorageProvider = defaultStorageProvider
+	}
+	if s.Image == "" {
+		s.Image = S3syncImage
+	}
+	if s.Version == "" {
+		s.Version = S3syncVersion
+	}
+}
+
 // Validate the AirflowCluster
 func (b *AirflowCluster) Validate() error {
 	errs := field.ErrorList{}
@@ -530,6 +1049,12 @@ func (b *AirflowCluster) Validate() error {
 	errs = append(errs, b.Spec.Scheduler.validate(spec.Child("scheduler"))...)
 	errs = append(errs, b.Spec.Worker.validate(spec.Child("worker"))...)
 	errs = append(errs, b.Spec.DAGs.validate(spec.Child("dags"))...)
+	errs = append(errs, b.Spec.Plugins.validate(spec.Child("plugins"))...)
+	errs = append(errs, b.Spec.Logging.validate(spec.Child("logging"))...)
+	errs = append(errs, b.Spec.LogVolume.validate(spec.Child("logVolume"))...)
+	errs = append(errs, b.Spec.SecretsBackend.validate(spec.Child("secretsBackend"), &b.Spec)...)
+	errs = append(errs, b.Spec.Metrics.validate(spec.Child("metrics"))...)
+	errs = append(errs, validateConfigSections(b.Spec.Config.Sections, spec.Child("config", "sections"))...)
 	errs = append(errs, b.Spec.UI.validate(spec.Child("ui"))...)
 	errs = append(errs, b.Spec.Flower.validate(spec.Child("flower"))...)
 
@@ -556,9 +1081,25 @@ func (b *AirflowCluster) Validate() error {
 		}
 	}
 	if b.Spec.Executor == ExecutorK8s {
+		if b.Spec.DAGs != nil && b.Spec.DAGs.Git != nil && b.Spec.DAGs.Git.Validation != nil &&
+			b.Spec.Scheduler != nil && versionBefore(b.Spec.Scheduler.Version, gitSyncRevMinVersion) {
+			errs = append(errs, field.Invalid(spec.Child("dags", "git", "validation"), "", "task pods of the Kubernetes executor are pinned to the validated commit with git_sync_rev, which needs airflow 1.10.10 or later"))
+		}
 		if b.Spec.Worker == nil {
 			errs = append(errs, field.Required(spec.Child("worker"), "worker required for Celery executor"))
 		}
+		if b.Spec.Logging != nil && b.Spec.Logging.Storage.StorageProvider == LogProviderGCS {
+			errs = append(errs, field.Invalid(spec.Child("logging", "storage", "storageprovider"), LogProviderGCS, "the service account key cannot be mounted into task pods of the Kubernetes executor"))
+		}
+		if b.Spec.SecretsBackend != nil {
+			errs = append(errs, field.Invalid(spec.Child("secretsBackend"), "", "the backend module cannot be mounted into task pods of the Kubernetes executor"))
+		}
+		if b.Spec.DAGs != nil && b.Spec.DAGs.Storage != nil {
+			errs = append(errs, field.Invalid(spec.Child("dags", "storage"), "", "task pods of the Kubernetes executor only sync DAGs from git"))
+		}
+		if b.Spec.Plugins != nil && b.Spec.Plugins.Storage != nil {
+			errs = append(errs, field.Invalid(spec.Child("plugins", "storage"), "", "task pods of the Kubernetes executor cannot sync plugins from storage"))
+		}
 	}
 
 	if b.Spec.Flower != nil {
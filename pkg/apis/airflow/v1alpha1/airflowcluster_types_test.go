@@ -20,8 +20,11 @@ import (
 
 	"github.com/onsi/gomega"
 	"golang.org/x/net/context"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 func TestStorageAirflowCluster(t *testing.T) {
@@ -55,3 +58,126 @@ func TestStorageAirflowCluster(t *testing.T) {
 	g.Expect(c.Delete(context.TODO(), fetched)).NotTo(gomega.HaveOccurred())
 	g.Expect(c.Get(context.TODO(), key, fetched)).To(gomega.HaveOccurred())
 }
+
+func TestGCSSpecValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		spec *GCSSpec
+		errs []string
+	}{
+		{"nil", nil, nil},
+		{"sidecar", &GCSSpec{Bucket: "b"}, nil},
+		{"no bucket", &GCSSpec{}, []string{"gcs.bucket"}},
+		{"once with prefix", &GCSSpec{Bucket: "b", Once: true, Prefix: "v1"}, nil},
+		{"once with object", &GCSSpec{Bucket: "b", Once: true, Object: "dags.tar.gz", Generation: 7}, nil},
+		{"once unpinned", &GCSSpec{Bucket: "b", Once: true}, []string{"gcs.prefix"}},
+		{"prefix without once", &GCSSpec{Bucket: "b", Prefix: "v1"}, []string{"gcs.prefix"}},
+		{"object without once", &GCSSpec{Bucket: "b", Object: "dags.tar.gz", Generation: 7}, []string{"gcs.object"}},
+		{"prefix and object", &GCSSpec{Bucket: "b", Once: true, Prefix: "v1", Object: "dags.tar.gz", Generation: 7}, []string{"gcs.object"}},
+		{"object without generation", &GCSSpec{Bucket: "b", Once: true, Object: "dags.tar.gz"}, []string{"gcs.generation"}},
+		{"generation without object", &GCSSpec{Bucket: "b", Once: true, Prefix: "v1", Generation: 7}, []string{"gcs.object"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			fields := []string{}
+			for _, err := range tt.spec.validate(field.NewPath("gcs")) {
+				fields = append(fields, err.Field)
+			}
+			if tt.errs == nil {
+				g.Expect(fields).To(gomega.BeEmpty())
+			} else {
+				g.Expect(fields).To(gomega.Equal(tt.errs))
+			}
+		})
+	}
+}
+
+func TestVersionBefore(t *testing.T) {
+	tests := []struct {
+		version string
+		before  bool
+	}{
+		{"1.10.2", true},
+		{"1.10.10", false},
+		{"1.10.12", false},
+		{"1.9", true},
+		{"2.1.0-python3.8", false},
+		{"1.10.4-python3.6", true},
+		{"latest", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(versionBefore(tt.version, []int{1, 10, 10})).To(gomega.Equal(tt.before))
+		})
+	}
+}
+
+func TestStorageSync(t *testing.T) {
+	storage := func() *StorageSpec {
+		return &StorageSpec{
+			SecretRef: &corev1.LocalObjectReference{Name: "s3"},
+			Config:    map[string]string{"endpoint": "https://s3", "region": "us-east-1", "bucket": "dags"},
+		}
+	}
+	tests := []struct {
+		name     string
+		executor string
+		dags     *StorageSpec
+		plugins  *StorageSpec
+		errs     []string
+	}{
+		{"celery", ExecutorCelery, storage(), storage(), nil},
+		{"kubernetes dags", ExecutorK8s, storage(), nil, []string{"spec.dags.storage"}},
+		{"kubernetes plugins", ExecutorK8s, nil, storage(), []string{"spec.plugins.storage"}},
+		{"kubernetes git", ExecutorK8s, nil, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			r := &AirflowCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+				Spec: AirflowClusterSpec{
+					Executor:       tt.executor,
+					Redis:          &RedisSpec{},
+					Scheduler:      &SchedulerSpec{},
+					Worker:         &WorkerSpec{},
+					AirflowBaseRef: &corev1.LocalObjectReference{Name: "foo"},
+				},
+			}
+			if tt.dags != nil {
+				r.Spec.DAGs = &DagSpec{Storage: tt.dags}
+			} else {
+				r.Spec.DAGs = &DagSpec{Git: &GitSpec{Repo: "https://github.com/foo/dags"}}
+			}
+			if tt.plugins != nil {
+				r.Spec.Plugins = &PluginsSpec{Storage: tt.plugins}
+			}
+			r.ApplyDefaults()
+			if tt.dags != nil {
+				g.Expect(tt.dags.Image + ":" + tt.dags.Version).To(gomega.Equal(S3syncImage + ":" + S3syncVersion))
+			}
+
+			fields := []string{}
+			if err := r.Validate(); err != nil {
+				for _, e := range err.(utilerrors.Aggregate).Errors() {
+					fields = append(fields, e.(*field.Error).Field)
+				}
+			}
+			if tt.errs == nil {
+				g.Expect(fields).To(gomega.BeEmpty())
+			} else {
+				g.Expect(fields).To(gomega.Equal(tt.errs))
+			}
+		})
+	}
+
+	// a configured image is kept
+	g := gomega.NewGomegaWithT(t)
+	s := storage()
+	s.Image, s.Version = "amazon/aws-cli", "2.0.6"
+	s.applySyncDefaults()
+	g.Expect(s.Image + ":" + s.Version).To(gomega.Equal("amazon/aws-cli:2.0.6"))
+}
@@ -0,0 +1,172 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sort"
+	"strings"
+)
+
+// airflowConfigKeys lists the airflow.cfg keys of Airflow 1.10 per section.
+// A nil list allows any key in the section.
+var airflowConfigKeys = map[string][]string{
+	"core": {
+		"airflow_home", "dags_folder", "base_log_folder", "remote_logging", "remote_log_conn_id",
+		"remote_base_log_folder", "encrypt_s3_logs", "logging_level", "fab_logging_level",
+		"logging_config_class", "log_format", "simple_log_format", "log_filename_template",
+		"log_processor_filename_template", "dag_processor_manager_log_location", "hostname_callable",
+		"default_timezone", "executor", "sql_alchemy_conn", "sql_engine_encoding",
+		"sql_alchemy_pool_enabled", "sql_alchemy_pool_size", "sql_alchemy_pool_recycle",
+		"sql_alchemy_reconnect_timeout", "sql_alchemy_schema", "parallelism", "dag_concurrency",
+		"dags_are_paused_at_creation", "non_pooled_task_slot_count", "max_active_runs_per_dag",
+		"load_examples", "plugins_folder", "fernet_key", "donot_pickle", "dagbag_import_timeout",
+		"task_runner", "default_impersonation", "security", "secure_mode", "unit_test_mode",
+		"task_log_reader", "enable_xcom_pickling", "killed_task_cleanup_time",
+		"dag_run_conf_overrides_params", "worker_precheck", "dag_discovery_safe_mode",
+	},
+	"cli":       {"api_client", "endpoint_url"},
+	"api":       {"auth_backend"},
+	"lineage":   {"backend"},
+	"atlas":     {"sasl_enabled", "host", "port", "username", "password"},
+	"operators": {"default_owner", "default_cpus", "default_ram", "default_disk", "default_gpus"},
+	"hive":      {"default_hive_mapred_queue"},
+	"webserver": {
+		"base_url", "web_server_host", "web_server_port", "web_server_ssl_cert", "web_server_ssl_key",
+		"web_server_master_timeout", "web_server_worker_timeout", "worker_refresh_batch_size",
+		"worker_refresh_interval", "secret_key", "workers", "worker_class", "access_logfile",
+		"error_logfile", "expose_config", "authenticate", "auth_backend", "filter_by_owner",
+		"owner_mode", "dag_default_view", "dag_orientation", "demo_mode", "log_fetch_timeout_sec",
+		"hide_paused_dags_by_default", "page_size", "rbac", "navbar_color",
+		"default_dag_run_display_number", "enable_proxy_fix", "cookie_secure", "cookie_samesite",
+	},
+	"email": {"email_backend"},
+	"smtp": {
+		"smtp_host", "smtp_starttls", "smtp_ssl", "smtp_user", "smtp_password", "smtp_port",
+		"smtp_mail_from",
+	},
+	"celery": {
+		"celery_app_name", "worker_concurrency", "worker_autoscale", "worker_log_server_port",
+		"broker_url", "result_backend", "flower_host", "flower_url_prefix", "flower_port",
+		"flower_basic_auth", "default_queue", "celery_config_options", "ssl_active", "ssl_key",
+		"ssl_cert", "ssl_cacert",
+	},
+	"celery_broker_transport_options": nil,
+	"dask":                            {"cluster_address", "tls_ca", "tls_cert", "tls_key"},
+	"scheduler": {
+		"job_heartbeat_sec", "scheduler_heartbeat_sec", "run_duration", "min_file_process_interval",
+		"dag_dir_list_interval", "print_stats_interval", "scheduler_health_check_threshold",
+		"child_process_log_directory", "scheduler_zombie_task_threshold", "catchup_by_default",
+		"max_tis_per_query", "statsd_on", "statsd_host", "statsd_port", "statsd_prefix",
+		"max_threads", "authenticate", "use_job_schedule",
+	},
+	"ldap": {
+		"uri", "user_filter", "user_name_attr", "group_member_attr", "superuser_filter",
+		"data_profiler_filter", "bind_user", "bind_password", "basedn", "cacert", "search_scope",
+		"ignore_malformed_schema",
+	},
+	"mesos": {
+		"master", "framework_name", "task_cpu", "task_memory", "checkpoint", "failover_timeout",
+		"authenticate", "default_principal", "default_secret", "docker_image_slave",
+	},
+	"kerberos":          {"ccache", "principal", "reinit_frequency", "kinit_path", "keytab"},
+	"github_enterprise": {"api_rev"},
+	"admin":             {"hide_sensitive_variable_fields"},
+	"elasticsearch": {
+		"elasticsearch_host", "elasticsearch_log_id_template", "elasticsearch_end_of_log_mark",
+		"host", "log_id_template", "end_of_log_mark",
+	},
+	"kubernetes": {
+		"worker_container_repository", "worker_container_tag", "worker_container_image_pull_policy",
+		"delete_worker_pods", "worker_pods_creation_batch_size", "namespace", "airflow_configmap",
+		"dags_in_image", "dags_volume_subpath", "dags_volume_claim", "logs_volume_subpath",
+		"logs_volume_claim", "dags_volume_host", "logs_volume_host", "git_repo", "git_branch",
+		"git_subpath", "git_user", "git_password", "git_sync_root", "git_sync_dest", "git_sync_rev",
+		"git_dags_folder_mount_point", "git_ssh_key_secret_name", "git_ssh_known_hosts_configmap_name",
+		"git_sync_container_repository", "git_sync_container_tag", "git_sync_init_container_name",
+		"worker_service_account_name", "image_pull_secrets", "gcp_service_account_keys", "in_cluster",
+		"cluster_context", "config_file", "affinity", "tolerations", "run_as_user", "fs_group",
+		"worker_dags_folder", "kube_client_request_args",
+	},
+	"kubernetes_node_selectors":        nil,
+	"kubernetes_annotations":           nil,
+	"kubernetes_environment_variables": nil,
+	"kubernetes_secrets":               nil,
+}
+
+// airflowManagedKeys are set by the operator and cannot be overridden. The
+// plugins, logging and statsd keys are owned by spec.plugins, spec.logging
+// and spec.metrics.
+var airflowManagedKeys = map[string][]string{
+	"core": {"executor", "sql_alchemy_conn", "dags_folder", "fernet_key", "plugins_folder",
+		"remote_logging", "remote_base_log_folder", "remote_log_conn_id"},
+	"celery":     {"broker_url", "result_backend"},
+	"scheduler":  {"statsd_on", "statsd_host", "statsd_port"},
+	"webserver":  {"secret_key", "rbac", "web_server_ssl_cert", "web_server_ssl_key"},
+	"kubernetes": {"airflow_configmap", "namespace", "git_sync_rev"},
+}
+
+func validateConfigSections(sections map[string]map[string]string, fp *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	names := []string{}
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		known, ok := airflowConfigKeys[name]
+		if !ok {
+			errs = append(errs, field.NotSupported(fp.Key(name), name, configSectionNames()))
+			continue
+		}
+		keys := []string{}
+		for key := range sections[name] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value := sections[name][key]
+			if known != nil && !containsString(known, key) {
+				errs = append(errs, field.NotSupported(fp.Key(name).Key(key), key, known))
+			}
+			if containsString(airflowManagedKeys[name], key) {
+				errs = append(errs, field.Invalid(fp.Key(name).Key(key), value, "set by the operator"))
+			}
+			if strings.ContainsAny(value, "\n\r") {
+				errs = append(errs, field.Invalid(fp.Key(name).Key(key), value, "must be a single line"))
+			}
+		}
+	}
+	return errs
+}
+
+func configSectionNames() []string {
+	names := []string{}
+	for name := range airflowConfigKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
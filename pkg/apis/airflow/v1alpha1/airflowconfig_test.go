@@ -0,0 +1,59 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateConfigSections(t *testing.T) {
+	tests := []struct {
+		name     string
+		sections map[string]map[string]string
+		errs     []string
+	}{
+		{"empty", nil, nil},
+		{"known key", map[string]map[string]string{"core": {"parallelism": "8"}}, nil},
+		{"any key", map[string]map[string]string{"kubernetes_annotations": {"foo": "bar"}}, nil},
+		{"unknown section", map[string]map[string]string{"cor": {"parallelism": "8"}}, []string{"spec.config.sections[cor]"}},
+		{"unknown key", map[string]map[string]string{"core": {"paralelism": "8"}}, []string{"spec.config.sections[core][paralelism]"}},
+		{"managed key", map[string]map[string]string{"core": {"executor": "LocalExecutor"}}, []string{"spec.config.sections[core][executor]"}},
+		{"managed plugins", map[string]map[string]string{"core": {"plugins_folder": "/p"}}, []string{"spec.config.sections[core][plugins_folder]"}},
+		{"managed statsd", map[string]map[string]string{"scheduler": {"statsd_on": "True"}}, []string{"spec.config.sections[scheduler][statsd_on]"}},
+		{"managed broker", map[string]map[string]string{"celery": {"broker_url": "redis://r"}}, []string{"spec.config.sections[celery][broker_url]"}},
+		{"multi line", map[string]map[string]string{"core": {"parallelism": "8\n[core]"}}, []string{"spec.config.sections[core][parallelism]"}},
+		{"sorted", map[string]map[string]string{"core": {"fernet_key": "k", "dags_folder": "/d"}},
+			[]string{"spec.config.sections[core][dags_folder]", "spec.config.sections[core][fernet_key]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			errs := validateConfigSections(tt.sections, field.NewPath("spec", "config", "sections"))
+			fields := []string{}
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			if tt.errs == nil {
+				g.Expect(fields).To(gomega.BeEmpty())
+			} else {
+				g.Expect(fields).To(gomega.Equal(tt.errs))
+			}
+		})
+	}
+}
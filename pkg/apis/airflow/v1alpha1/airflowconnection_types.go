@@ -0,0 +1,151 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-reconciler/pkg/finalizer"
+	"sigs.k8s.io/controller-reconciler/pkg/status"
+)
+
+// AirflowConnectionSpec defines the desired state of AirflowConnection
+type AirflowConnectionSpec struct {
+	// AirflowClusterRef is a reference to the AirflowCluster whose metadata DB holds the connection
+	AirflowClusterRef *corev1.LocalObjectReference `json:"airflowcluster,omitempty"`
+	// ConnID is the Airflow conn_id. Defaults to the name of the resource
+	// +optional
+	ConnID string `json:"connId,omitempty"`
+	// ConnType is the Airflow connection type e.g. postgres, http, aws
+	ConnType string `json:"connType,omitempty"`
+	// Host of the connection
+	// +optional
+	Host string `json:"host,omitempty"`
+	// Port of the connection
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// Schema of the connection
+	// +optional
+	Schema string `json:"schema,omitempty"`
+	// Login of the connection. The login key of SecretRef takes precedence
+	// +optional
+	Login string `json:"login,omitempty"`
+	// Extra is the json object passed to hooks as connection extras
+	// +optional
+	Extra string `json:"extra,omitempty"`
+	// SecretRef is a secret in the namespace with the login and password keys
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+// MetadataSyncStatus is the status of an object kept in the metadata DB of an AirflowCluster
+type MetadataSyncStatus struct {
+	status.Meta `json:",inline"`
+	// LastSyncTime is when the metadata DB was last found matching or updated to match the resource
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// LastDriftTime is when the object in the metadata DB was last found
+	// changed outside of the resource and overwritten
+	// +optional
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+	// SyncedVersion is a digest of the spec and the secret versions last written
+	// +optional
+	SyncedVersion string `json:"syncedVersion,omitempty"`
+	// SyncedID is the key of the row last written, the row is removed when the key changes
+	// +optional
+	SyncedID string `json:"syncedId,omitempty"`
+	// SyncJob is the Job writing to the metadata DB while it runs
+	// +optional
+	SyncJob string `json:"syncJob,omitempty"`
+}
+
+// AirflowConnectionStatus defines the observed state of AirflowConnection
+type AirflowConnectionStatus struct {
+	MetadataSyncStatus `json:",inline"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AirflowConnection is an Airflow connection kept in the metadata DB of an AirflowCluster
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=airflowconnections
+type AirflowConnection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AirflowConnectionSpec   `json:"spec,omitempty"`
+	Status AirflowConnectionStatus `json:"status,omitempty"`
+}
+
+// ApplyDefaults the AirflowConnection
+func (b *AirflowConnection) ApplyDefaults() {
+	if b.Spec.ConnID == "" {
+		b.Spec.ConnID = b.Name
+	}
+	finalizer.EnsureStandard(b)
+}
+
+// Validate the AirflowConnection
+func (b *AirflowConnection) Validate() error {
+	errs := field.ErrorList{}
+	spec := field.NewPath("spec")
+
+	errs = append(errs, validateClusterRef(b.Spec.AirflowClusterRef, spec.Child("airflowcluster"))...)
+	if b.Spec.ConnType == "" {
+		errs = append(errs, field.Required(spec.Child("connType"), "connection type missing"))
+	}
+	if b.Spec.Port < 0 || b.Spec.Port > 65535 {
+		errs = append(errs, field.Invalid(spec.Child("port"), b.Spec.Port, "port out of range"))
+	}
+	if b.Spec.Extra != "" {
+		extra := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(b.Spec.Extra), &extra); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("extra"), b.Spec.Extra, "must be a json object"))
+		}
+	}
+	if b.Spec.SecretRef != nil && b.Spec.SecretRef.Name == "" {
+		errs = append(errs, field.Required(spec.Child("secretRef", "name"), "name missing"))
+	}
+
+	return errs.ToAggregate()
+}
+
+// validateClusterRef checks the reference to the AirflowCluster of a metadata DB object
+func validateClusterRef(ref *corev1.LocalObjectReference, fp *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if ref == nil {
+		errs = append(errs, field.Required(fp, "airflowcluster reference missing"))
+	} else if ref.Name == "" {
+		errs = append(errs, field.Required(fp.Child("name"), "name missing"))
+	}
+	return errs
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AirflowConnectionList contains a list of AirflowConnection
+type AirflowConnectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AirflowConnection `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AirflowConnection{}, &AirflowConnectionList{})
+}
@@ -0,0 +1,62 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAirflowConnectionValidate(t *testing.T) {
+	ref := &corev1.LocalObjectReference{Name: "af"}
+	tests := []struct {
+		name  string
+		spec  AirflowConnectionSpec
+		valid bool
+	}{
+		{"minimal", AirflowConnectionSpec{AirflowClusterRef: ref, ConnType: "http"}, true},
+		{"no cluster", AirflowConnectionSpec{ConnType: "http"}, false},
+		{"unnamed cluster", AirflowConnectionSpec{AirflowClusterRef: &corev1.LocalObjectReference{}, ConnType: "http"}, false},
+		{"no type", AirflowConnectionSpec{AirflowClusterRef: ref}, false},
+		{"port", AirflowConnectionSpec{AirflowClusterRef: ref, ConnType: "http", Port: 443}, true},
+		{"port out of range", AirflowConnectionSpec{AirflowClusterRef: ref, ConnType: "http", Port: 65536}, false},
+		{"extra object", AirflowConnectionSpec{AirflowClusterRef: ref, ConnType: "http", Extra: `{"a": 1}`}, true},
+		{"extra list", AirflowConnectionSpec{AirflowClusterRef: ref, ConnType: "http", Extra: `[1]`}, false},
+		{"unnamed secret", AirflowConnectionSpec{AirflowClusterRef: ref, ConnType: "http", SecretRef: &corev1.LocalObjectReference{}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			conn := &AirflowConnection{Spec: tt.spec}
+			if tt.valid {
+				g.Expect(conn.Validate()).To(gomega.Succeed())
+			} else {
+				g.Expect(conn.Validate()).NotTo(gomega.Succeed())
+			}
+		})
+	}
+}
+
+func TestAirflowConnectionDefaults(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	conn := &AirflowConnection{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}
+	conn.ApplyDefaults()
+	g.Expect(conn.Spec.ConnID).To(gomega.Equal("foo"))
+	g.Expect(conn.Finalizers).NotTo(gomega.BeEmpty())
+}
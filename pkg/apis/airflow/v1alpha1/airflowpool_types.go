@@ -0,0 +1,90 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-reconciler/pkg/finalizer"
+)
+
+// AirflowPoolSpec defines the desired state of AirflowPool
+type AirflowPoolSpec struct {
+	// AirflowClusterRef is a reference to the AirflowCluster whose metadata DB holds the pool
+	AirflowClusterRef *corev1.LocalObjectReference `json:"airflowcluster,omitempty"`
+	// Pool is the name of the pool. Defaults to the name of the resource
+	// +optional
+	Pool string `json:"pool,omitempty"`
+	// Slots is the number of task instances that may run in the pool at once
+	Slots int32 `json:"slots,omitempty"`
+	// Description of the pool
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// AirflowPoolStatus defines the observed state of AirflowPool
+type AirflowPoolStatus struct {
+	MetadataSyncStatus `json:",inline"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AirflowPool is an Airflow pool kept in the metadata DB of an AirflowCluster
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=airflowpools
+type AirflowPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AirflowPoolSpec   `json:"spec,omitempty"`
+	Status AirflowPoolStatus `json:"status,omitempty"`
+}
+
+// ApplyDefaults the AirflowPool
+func (b *AirflowPool) ApplyDefaults() {
+	if b.Spec.Pool == "" {
+		b.Spec.Pool = b.Name
+	}
+	finalizer.EnsureStandard(b)
+}
+
+// Validate the AirflowPool
+func (b *AirflowPool) Validate() error {
+	errs := field.ErrorList{}
+	spec := field.NewPath("spec")
+
+	errs = append(errs, validateClusterRef(b.Spec.AirflowClusterRef, spec.Child("airflowcluster"))...)
+	if b.Spec.Slots < 0 {
+		errs = append(errs, field.Invalid(spec.Child("slots"), b.Spec.Slots, "must not be negative"))
+	}
+
+	return errs.ToAggregate()
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AirflowPoolList contains a list of AirflowPool
+type AirflowPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AirflowPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AirflowPool{}, &AirflowPoolList{})
+}
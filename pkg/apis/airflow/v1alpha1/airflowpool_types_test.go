@@ -0,0 +1,59 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAirflowPoolValidate(t *testing.T) {
+	ref := &corev1.LocalObjectReference{Name: "af"}
+	tests := []struct {
+		name  string
+		spec  AirflowPoolSpec
+		valid bool
+	}{
+		{"minimal", AirflowPoolSpec{AirflowClusterRef: ref}, true},
+		{"slots", AirflowPoolSpec{AirflowClusterRef: ref, Slots: 8}, true},
+		{"no cluster", AirflowPoolSpec{Slots: 8}, false},
+		{"negative slots", AirflowPoolSpec{AirflowClusterRef: ref, Slots: -1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			pool := &AirflowPool{Spec: tt.spec}
+			if tt.valid {
+				g.Expect(pool.Validate()).To(gomega.Succeed())
+			} else {
+				g.Expect(pool.Validate()).NotTo(gomega.Succeed())
+			}
+		})
+	}
+}
+
+func TestAirflowPoolDefaults(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	pool := &AirflowPool{ObjectMeta: metav1.ObjectMeta{Name: "foo"}, Spec: AirflowPoolSpec{Pool: "bar"}}
+	pool.ApplyDefaults()
+	g.Expect(pool.Spec.Pool).To(gomega.Equal("bar"))
+	pool.Spec.Pool = ""
+	pool.ApplyDefaults()
+	g.Expect(pool.Spec.Pool).To(gomega.Equal("foo"))
+}
@@ -0,0 +1,105 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-reconciler/pkg/finalizer"
+)
+
+// AirflowVariableSpec defines the desired state of AirflowVariable
+type AirflowVariableSpec struct {
+	// AirflowClusterRef is a reference to the AirflowCluster whose metadata DB holds the variable
+	AirflowClusterRef *corev1.LocalObjectReference `json:"airflowcluster,omitempty"`
+	// Key of the variable. Defaults to the name of the resource
+	// +optional
+	Key string `json:"key,omitempty"`
+	// Value of the variable
+	// +optional
+	Value string `json:"value,omitempty"`
+	// SecretRef selects a secret key holding the value
+	// +optional
+	SecretRef *corev1.SecretKeySelector `json:"secretRef,omitempty"`
+	// JSON marks the value as json for Variable.get(key, deserialize_json=True)
+	// +optional
+	JSON bool `json:"json,omitempty"`
+}
+
+// AirflowVariableStatus defines the observed state of AirflowVariable
+type AirflowVariableStatus struct {
+	MetadataSyncStatus `json:",inline"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AirflowVariable is an Airflow variable kept in the metadata DB of an AirflowCluster
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=airflowvariables
+type AirflowVariable struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AirflowVariableSpec   `json:"spec,omitempty"`
+	Status AirflowVariableStatus `json:"status,omitempty"`
+}
+
+// ApplyDefaults the AirflowVariable
+func (b *AirflowVariable) ApplyDefaults() {
+	if b.Spec.Key == "" {
+		b.Spec.Key = b.Name
+	}
+	finalizer.EnsureStandard(b)
+}
+
+// Validate the AirflowVariable
+func (b *AirflowVariable) Validate() error {
+	errs := field.ErrorList{}
+	spec := field.NewPath("spec")
+
+	errs = append(errs, validateClusterRef(b.Spec.AirflowClusterRef, spec.Child("airflowcluster"))...)
+	if b.Spec.SecretRef != nil {
+		if b.Spec.Value != "" {
+			errs = append(errs, field.Invalid(spec.Child("value"), "", "only one of value and secretRef may be set"))
+		}
+		if b.Spec.SecretRef.Name == "" {
+			errs = append(errs, field.Required(spec.Child("secretRef", "name"), "name missing"))
+		}
+		if b.Spec.SecretRef.Key == "" {
+			errs = append(errs, field.Required(spec.Child("secretRef", "key"), "key missing"))
+		}
+	} else if b.Spec.JSON && !json.Valid([]byte(b.Spec.Value)) {
+		errs = append(errs, field.Invalid(spec.Child("value"), b.Spec.Value, "must be json"))
+	}
+
+	return errs.ToAggregate()
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AirflowVariableList contains a list of AirflowVariable
+type AirflowVariableList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AirflowVariable `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AirflowVariable{}, &AirflowVariableList{})
+}
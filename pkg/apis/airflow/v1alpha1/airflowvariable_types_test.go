@@ -0,0 +1,63 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAirflowVariableValidate(t *testing.T) {
+	ref := &corev1.LocalObjectReference{Name: "af"}
+	secret := &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "s"}, Key: "k"}
+	tests := []struct {
+		name  string
+		spec  AirflowVariableSpec
+		valid bool
+	}{
+		{"value", AirflowVariableSpec{AirflowClusterRef: ref, Value: "v"}, true},
+		{"no cluster", AirflowVariableSpec{Value: "v"}, false},
+		{"json", AirflowVariableSpec{AirflowClusterRef: ref, Value: `{"a": [1]}`, JSON: true}, true},
+		{"bad json", AirflowVariableSpec{AirflowClusterRef: ref, Value: `{"a"`, JSON: true}, false},
+		{"secret", AirflowVariableSpec{AirflowClusterRef: ref, SecretRef: secret}, true},
+		{"secret and value", AirflowVariableSpec{AirflowClusterRef: ref, SecretRef: secret, Value: "v"}, false},
+		{"secret without key", AirflowVariableSpec{AirflowClusterRef: ref,
+			SecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "s"}}}, false},
+		{"json from secret", AirflowVariableSpec{AirflowClusterRef: ref, SecretRef: secret, JSON: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			variable := &AirflowVariable{Spec: tt.spec}
+			if tt.valid {
+				g.Expect(variable.Validate()).To(gomega.Succeed())
+			} else {
+				g.Expect(variable.Validate()).NotTo(gomega.Succeed())
+			}
+		})
+	}
+}
+
+func TestAirflowVariableDefaults(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	variable := &AirflowVariable{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}
+	variable.ApplyDefaults()
+	g.Expect(variable.Spec.Key).To(gomega.Equal("foo"))
+	g.Expect(variable.Finalizers).NotTo(gomega.BeEmpty())
+}
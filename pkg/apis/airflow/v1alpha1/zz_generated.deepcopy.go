@@ -290,6 +290,31 @@ func (in *AirflowClusterSpec) DeepCopyInto(out *AirflowClusterSpec) {
 		*out = new(DagSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = new(PluginsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(LoggingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogVolume != nil {
+		in, out := &in.LogVolume, &out.LogVolume
+		*out = new(LogVolumeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretsBackend != nil {
+		in, out := &in.SecretsBackend, &out.SecretsBackend
+		*out = new(SecretsBackendSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(MetricsSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AirflowBaseRef != nil {
 		in, out := &in.AirflowBaseRef, &out.AirflowBaseRef
 		*out = new(v1.LocalObjectReference)
@@ -313,150 +338,710 @@ func (in *AirflowClusterStatus) DeepCopyInto(out *AirflowClusterStatus) {
 	*out = *in
 	in.Meta.DeepCopyInto(&out.Meta)
 	in.ComponentMeta.DeepCopyInto(&out.ComponentMeta)
+	if in.DAGs != nil {
+		in, out := &in.DAGs, &out.DAGs
+		*out = new(DagStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduler != nil {
+		in, out := &in.Scheduler, &out.Scheduler
+		*out = new(SchedulerStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DagValidation != nil {
+		in, out := &in.DagValidation, &out.DagValidation
+		*out = new(DagValidationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FernetKey != nil {
+		in, out := &in.FernetKey, &out.FernetKey
+		*out = new(FernetKeyStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AirflowClusterStatus.
+func (in *AirflowClusterStatus) DeepCopy() *AirflowClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AirflowClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AirflowConnection) DeepCopyInto(out *AirflowConnection) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AirflowConnection.
+func (in *AirflowConnection) DeepCopy() *AirflowConnection {
+	if in == nil {
+		return nil
+	}
+	out := new(AirflowConnection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AirflowConnection) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AirflowConnectionList) DeepCopyInto(out *AirflowConnectionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AirflowConnection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AirflowConnectionList.
+func (in *AirflowConnectionList) DeepCopy() *AirflowConnectionList {
+	if in == nil {
+		return nil
+	}
+	out := new(AirflowConnectionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AirflowConnectionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AirflowConnectionSpec) DeepCopyInto(out *AirflowConnectionSpec) {
+	*out = *in
+	if in.AirflowClusterRef != nil {
+		in, out := &in.AirflowClusterRef, &out.AirflowClusterRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AirflowConnectionSpec.
+func (in *AirflowConnectionSpec) DeepCopy() *AirflowConnectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AirflowConnectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AirflowConnectionStatus) DeepCopyInto(out *AirflowConnectionStatus) {
+	*out = *in
+	in.MetadataSyncStatus.DeepCopyInto(&out.MetadataSyncStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AirflowConnectionStatus.
+func (in *AirflowConnectionStatus) DeepCopy() *AirflowConnectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AirflowConnectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AirflowPool) DeepCopyInto(out *AirflowPool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AirflowPool.
+func (in *AirflowPool) DeepCopy() *AirflowPool {
+	if in == nil {
+		return nil
+	}
+	out := new(AirflowPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AirflowPool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AirflowPoolList) DeepCopyInto(out *AirflowPoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AirflowPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AirflowPoolList.
+func (in *AirflowPoolList) DeepCopy() *AirflowPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(AirflowPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AirflowPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AirflowPoolSpec) DeepCopyInto(out *AirflowPoolSpec) {
+	*out = *in
+	if in.AirflowClusterRef != nil {
+		in, out := &in.AirflowClusterRef, &out.AirflowClusterRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AirflowPoolSpec.
+func (in *AirflowPoolSpec) DeepCopy() *AirflowPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AirflowPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AirflowPoolStatus) DeepCopyInto(out *AirflowPoolStatus) {
+	*out = *in
+	in.MetadataSyncStatus.DeepCopyInto(&out.MetadataSyncStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AirflowPoolStatus.
+func (in *AirflowPoolStatus) DeepCopy() *AirflowPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AirflowPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AirflowUISpec) DeepCopyInto(out *AirflowUISpec) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(IngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(UIAuthSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(UITLSSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AirflowUISpec.
+func (in *AirflowUISpec) DeepCopy() *AirflowUISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AirflowUISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AirflowVariable) DeepCopyInto(out *AirflowVariable) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AirflowVariable.
+func (in *AirflowVariable) DeepCopy() *AirflowVariable {
+	if in == nil {
+		return nil
+	}
+	out := new(AirflowVariable)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AirflowVariable) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AirflowVariableList) DeepCopyInto(out *AirflowVariableList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AirflowVariable, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AirflowVariableList.
+func (in *AirflowVariableList) DeepCopy() *AirflowVariableList {
+	if in == nil {
+		return nil
+	}
+	out := new(AirflowVariableList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AirflowVariableList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AirflowVariableSpec) DeepCopyInto(out *AirflowVariableSpec) {
+	*out = *in
+	if in.AirflowClusterRef != nil {
+		in, out := &in.AirflowClusterRef, &out.AirflowClusterRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AirflowVariableSpec.
+func (in *AirflowVariableSpec) DeepCopy() *AirflowVariableSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AirflowVariableSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AirflowVariableStatus) DeepCopyInto(out *AirflowVariableStatus) {
+	*out = *in
+	in.MetadataSyncStatus.DeepCopyInto(&out.MetadataSyncStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AirflowVariableStatus.
+func (in *AirflowVariableStatus) DeepCopy() *AirflowVariableStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AirflowVariableStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterConfig) DeepCopyInto(out *ClusterConfig) {
+	*out = *in
+	if in.AirflowEnv != nil {
+		in, out := &in.AirflowEnv, &out.AirflowEnv
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AirflowSecretEnv != nil {
+		in, out := &in.AirflowSecretEnv, &out.AirflowSecretEnv
+		*out = make([]SecretEnv, len(*in))
+		copy(*out, *in)
+	}
+	if in.Sections != nil {
+		in, out := &in.Sections, &out.Sections
+		*out = make(map[string]map[string]string, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterConfig.
+func (in *ClusterConfig) DeepCopy() *ClusterConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentDagStatus) DeepCopyInto(out *ComponentDagStatus) {
+	*out = *in
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentDagStatus.
+func (in *ComponentDagStatus) DeepCopy() *ComponentDagStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentDagStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DagSpec) DeepCopyInto(out *DagSpec) {
+	*out = *in
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NfsPV != nil {
+		in, out := &in.NfsPV, &out.NfsPV
+		*out = new(v1.PersistentVolumeClaim)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(StorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GCS != nil {
+		in, out := &in.GCS, &out.GCS
+		*out = new(GCSSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DagSpec.
+func (in *DagSpec) DeepCopy() *DagSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DagSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DagStatus) DeepCopyInto(out *DagStatus) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make(map[string]ComponentDagStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DagStatus.
+func (in *DagStatus) DeepCopy() *DagStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DagStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DagValidationSpec) DeepCopyInto(out *DagValidationSpec) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DagValidationSpec.
+func (in *DagValidationSpec) DeepCopy() *DagValidationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DagValidationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DagValidationStatus) DeepCopyInto(out *DagValidationStatus) {
+	*out = *in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DagValidationStatus.
+func (in *DagValidationStatus) DeepCopy() *DagValidationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DagValidationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FernetKeyStatus) DeepCopyInto(out *FernetKeyStatus) {
+	*out = *in
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FernetKeyStatus.
+func (in *FernetKeyStatus) DeepCopy() *FernetKeyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FernetKeyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowerSpec) DeepCopyInto(out *FlowerSpec) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(IngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AirflowClusterStatus.
-func (in *AirflowClusterStatus) DeepCopy() *AirflowClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowerSpec.
+func (in *FlowerSpec) DeepCopy() *FlowerSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(AirflowClusterStatus)
+	out := new(FlowerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AirflowUISpec) DeepCopyInto(out *AirflowUISpec) {
+func (in *GCSSpec) DeepCopyInto(out *GCSSpec) {
 	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AirflowUISpec.
-func (in *AirflowUISpec) DeepCopy() *AirflowUISpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCSSpec.
+func (in *GCSSpec) DeepCopy() *GCSSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(AirflowUISpec)
+	out := new(GCSSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterConfig) DeepCopyInto(out *ClusterConfig) {
+func (in *GitSpec) DeepCopyInto(out *GitSpec) {
 	*out = *in
-	if in.AirflowEnv != nil {
-		in, out := &in.AirflowEnv, &out.AirflowEnv
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.CredSecretRef != nil {
+		in, out := &in.CredSecretRef, &out.CredSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
 	}
-	if in.AirflowSecretEnv != nil {
-		in, out := &in.AirflowSecretEnv, &out.AirflowSecretEnv
-		*out = make([]SecretEnv, len(*in))
-		copy(*out, *in)
+	if in.SSHSecretRef != nil {
+		in, out := &in.SSHSecretRef, &out.SSHSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(DagValidationSpec)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterConfig.
-func (in *ClusterConfig) DeepCopy() *ClusterConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitSpec.
+func (in *GitSpec) DeepCopy() *GitSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterConfig)
+	out := new(GitSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DagSpec) DeepCopyInto(out *DagSpec) {
+func (in *IngressSpec) DeepCopyInto(out *IngressSpec) {
 	*out = *in
-	if in.Git != nil {
-		in, out := &in.Git, &out.Git
-		*out = new(GitSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.NfsPV != nil {
-		in, out := &in.NfsPV, &out.NfsPV
-		*out = new(v1.PersistentVolumeClaim)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Storage != nil {
-		in, out := &in.Storage, &out.Storage
-		*out = new(StorageSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.GCS != nil {
-		in, out := &in.GCS, &out.GCS
-		*out = new(GCSSpec)
-		**out = **in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DagSpec.
-func (in *DagSpec) DeepCopy() *DagSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressSpec.
+func (in *IngressSpec) DeepCopy() *IngressSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DagSpec)
+	out := new(IngressSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FlowerSpec) DeepCopyInto(out *FlowerSpec) {
+func (in *LDAPAuthSpec) DeepCopyInto(out *LDAPAuthSpec) {
 	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
+	if in.BindSecretRef != nil {
+		in, out := &in.BindSecretRef, &out.BindSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowerSpec.
-func (in *FlowerSpec) DeepCopy() *FlowerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPAuthSpec.
+func (in *LDAPAuthSpec) DeepCopy() *LDAPAuthSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(FlowerSpec)
+	out := new(LDAPAuthSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GCSSpec) DeepCopyInto(out *GCSSpec) {
+func (in *LogVolumeSpec) DeepCopyInto(out *LogVolumeSpec) {
 	*out = *in
+	if in.Claim != nil {
+		in, out := &in.Claim, &out.Claim
+		*out = new(v1.PersistentVolumeClaim)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCSSpec.
-func (in *GCSSpec) DeepCopy() *GCSSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogVolumeSpec.
+func (in *LogVolumeSpec) DeepCopy() *LogVolumeSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GCSSpec)
+	out := new(LogVolumeSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitSpec) DeepCopyInto(out *GitSpec) {
+func (in *LoggingSpec) DeepCopyInto(out *LoggingSpec) {
 	*out = *in
-	if in.CredSecretRef != nil {
-		in, out := &in.CredSecretRef, &out.CredSecretRef
-		*out = new(v1.LocalObjectReference)
-		**out = **in
-	}
+	in.Storage.DeepCopyInto(&out.Storage)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitSpec.
-func (in *GitSpec) DeepCopy() *GitSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingSpec.
+func (in *LoggingSpec) DeepCopy() *LoggingSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GitSpec)
+	out := new(LoggingSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -503,6 +1088,53 @@ func (in *MemoryStoreStatus) DeepCopy() *MemoryStoreStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataSyncStatus) DeepCopyInto(out *MetadataSyncStatus) {
+	*out = *in
+	in.Meta.DeepCopyInto(&out.Meta)
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastDriftTime != nil {
+		in, out := &in.LastDriftTime, &out.LastDriftTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataSyncStatus.
+func (in *MetadataSyncStatus) DeepCopy() *MetadataSyncStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataSyncStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsSpec) DeepCopyInto(out *MetricsSpec) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.ServiceMonitor != nil {
+		in, out := &in.ServiceMonitor, &out.ServiceMonitor
+		*out = new(ServiceMonitorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsSpec.
+func (in *MetricsSpec) DeepCopy() *MetricsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MySQLBackup) DeepCopyInto(out *MySQLBackup) {
 	*out = *in
@@ -581,6 +1213,89 @@ func (in *NFSStoreSpec) DeepCopy() *NFSStoreSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuthSpec) DeepCopyInto(out *OAuthSpec) {
+	*out = *in
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClientSecretRef != nil {
+		in, out := &in.ClientSecretRef, &out.ClientSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuthSpec.
+func (in *OAuthSpec) DeepCopy() *OAuthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordAuthSpec) DeepCopyInto(out *PasswordAuthSpec) {
+	*out = *in
+	if in.AdminSecretRef != nil {
+		in, out := &in.AdminSecretRef, &out.AdminSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordAuthSpec.
+func (in *PasswordAuthSpec) DeepCopy() *PasswordAuthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordAuthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginsSpec) DeepCopyInto(out *PluginsSpec) {
+	*out = *in
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(StorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GCS != nil {
+		in, out := &in.GCS, &out.GCS
+		*out = new(GCSSpec)
+		**out = **in
+	}
+	if in.Requirements != nil {
+		in, out := &in.Requirements, &out.Requirements
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginsSpec.
+func (in *PluginsSpec) DeepCopy() *PluginsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresSpec) DeepCopyInto(out *PostgresSpec) {
 	*out = *in
@@ -719,6 +1434,14 @@ func (in *SchedulerSpec) DeepCopy() *SchedulerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SchedulerStatus) DeepCopyInto(out *SchedulerStatus) {
 	*out = *in
+	if in.LastHeartbeatTime != nil {
+		in, out := &in.LastHeartbeatTime, &out.LastHeartbeatTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -748,6 +1471,52 @@ func (in *SecretEnv) DeepCopy() *SecretEnv {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretsBackendSpec) DeepCopyInto(out *SecretsBackendSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsBackendSpec.
+func (in *SecretsBackendSpec) DeepCopy() *SecretsBackendSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretsBackendSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMonitorSpec) DeepCopyInto(out *ServiceMonitorSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMonitorSpec.
+func (in *ServiceMonitorSpec) DeepCopy() *ServiceMonitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMonitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
 	*out = *in
@@ -776,6 +1545,53 @@ func (in *StorageSpec) DeepCopy() *StorageSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UIAuthSpec) DeepCopyInto(out *UIAuthSpec) {
+	*out = *in
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(PasswordAuthSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(LDAPAuthSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OAuth != nil {
+		in, out := &in.OAuth, &out.OAuth
+		*out = new(OAuthSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UIAuthSpec.
+func (in *UIAuthSpec) DeepCopy() *UIAuthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UIAuthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UITLSSpec) DeepCopyInto(out *UITLSSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UITLSSpec.
+func (in *UITLSSpec) DeepCopy() *UITLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UITLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkerSpec) DeepCopyInto(out *WorkerSpec) {
 	*out = *in
@@ -30,9 +30,11 @@ import (
 	alpha1 "k8s.io/airflow-operator/pkg/apis/airflow/v1alpha1"
 	"k8s.io/airflow-operator/pkg/controller/application"
 	"k8s.io/airflow-operator/pkg/controller/common"
+	"k8s.io/airflow-operator/pkg/metrics"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1beta1"
+	"sigs.k8s.io/controller-reconciler/pkg/finalizer"
 	gr "sigs.k8s.io/controller-reconciler/pkg/genericreconciler"
 	"sigs.k8s.io/controller-reconciler/pkg/reconciler"
 	"sigs.k8s.io/controller-reconciler/pkg/reconciler/manager/k8s"
@@ -44,31 +46,40 @@ import (
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
 	r := newReconciler(mgr)
-	return r.Controller(nil)
+	return r.Controller(metrics.NewReconciler(common.KindAirflowBase, r))
 }
 
+// newReconciler builds the reconciler of a manager. The handlers of a
+// manager share the events recorded on its AirflowBases.
 func newReconciler(mgr manager.Manager) *gr.Reconciler {
+	events := common.NewEvents(mgr.GetRecorder("airflowbase-controller"))
 	return gr.
 		WithManager(mgr).
 		For(&alpha1.AirflowBase{}, alpha1.SchemeGroupVersion).
-		Using(&MySQL{}).
-		Using(&Postgres{}).
-		Using(&SQLProxy{}).
-		Using(&NFS{}).
-		Using(&AirflowBase{}).
-		WithErrorHandler(handleError).
+		Using(&MySQL{events: events}).
+		Using(&Postgres{events: events}).
+		Using(&SQLProxy{events: events}).
+		Using(&NFS{events: events}).
+		Using(&AirflowBase{events: events}).
+		WithErrorHandler(errorHandler(events)).
 		WithValidator(validate).
 		WithDefaulter(applyDefaults).
 		RegisterSchemeBuilder(app.SchemeBuilder).
 		Build()
 }
 
-func handleError(resource interface{}, err error, kind string) {
-	ab := resource.(*alpha1.AirflowBase)
-	if err != nil {
-		ab.Status.SetError("ErrorSeen", err.Error())
-	} else {
-		ab.Status.ClearError()
+// errorHandler returns the error handler of the reconciler, it records the
+// errors with events
+func errorHandler(events *common.Events) func(interface{}, error, string) {
+	return func(resource interface{}, err error, errkind string) {
+		ab := resource.(*alpha1.AirflowBase)
+		if err != nil {
+			metrics.ReconcileError(common.KindAirflowBase, ab, errkind)
+			events.Error(ab, err, errkind)
+			ab.Status.SetError("ErrorSeen", err.Error())
+		} else {
+			ab.Status.ClearError()
+		}
 	}
 }
 
@@ -83,19 +94,29 @@ func applyDefaults(resource interface{}) {
 }
 
 // AirflowBase - interface to handle airflowbase
-type AirflowBase struct{}
+type AirflowBase struct {
+	events *common.Events
+}
 
 // MySQL - interface to handle redis
-type MySQL struct{}
+type MySQL struct {
+	events *common.Events
+}
 
 // Postgres  - interface to handle flower
-type Postgres struct{}
+type Postgres struct {
+	events *common.Events
+}
 
 // SQLProxy - interface to handle scheduler
-type SQLProxy struct{}
+type SQLProxy struct {
+	events *common.Events
+}
 
 // NFS - interface to handle worker
-type NFS struct{}
+type NFS struct {
+	events *common.Events
+}
 
 // =-------------------------- common ------------------------------------
 
@@ -115,6 +136,13 @@ func templateValue(r *alpha1.AirflowBase, component, altcomponent string, label,
 	}
 }
 
+// handlerDone records the reconcile of a handler in the operator metrics
+// and the events about the objects it reconciled
+func handlerDone(events *common.Events, rsrc interface{}, handler string, reconciled []reconciler.Object, err error) {
+	metrics.HandlerDone(common.KindAirflowBase, rsrc.(*alpha1.AirflowBase), handler, err)
+	events.Reconciled(rsrc.(*alpha1.AirflowBase), reconciled)
+}
+
 // updateStatus use reconciled objects to update component status
 func updateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
 	var period time.Duration
@@ -172,6 +200,9 @@ func (s *MySQL) Objects(rsrc interface{}, rsrclabels map[string]string, observed
 
 // UpdateStatus use reconciled objects to update component status
 func (s *MySQL) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	handlerDone(s.events, rsrc, "MySQL", reconciled, err)
+	r := rsrc.(*alpha1.AirflowBase)
+	common.UpdateComponentCondition(&r.Status.Meta, "MySQL", r.Spec.MySQL != nil, reconciled, err)
 	return updateStatus(rsrc, reconciled, err)
 }
 
@@ -223,6 +254,9 @@ func (s *Postgres) Objects(rsrc interface{}, rsrclabels map[string]string, obser
 
 // UpdateStatus use reconciled objects to update component status
 func (s *Postgres) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	handlerDone(s.events, rsrc, "Postgres", reconciled, err)
+	r := rsrc.(*alpha1.AirflowBase)
+	common.UpdateComponentCondition(&r.Status.Meta, "Postgres", r.Spec.Postgres != nil, reconciled, err)
 	return updateStatus(rsrc, reconciled, err)
 }
 
@@ -266,6 +300,9 @@ func (s *NFS) sts(o *reconciler.Object, v interface{}) {
 
 // UpdateStatus use reconciled objects to update component status
 func (s *NFS) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	handlerDone(s.events, rsrc, "NFS", reconciled, err)
+	r := rsrc.(*alpha1.AirflowBase)
+	common.UpdateComponentCondition(&r.Status.Meta, "NFS", r.Spec.Storage != nil, reconciled, err)
 	return updateStatus(rsrc, reconciled, err)
 }
 
@@ -308,6 +345,9 @@ func (s *SQLProxy) Objects(rsrc interface{}, rsrclabels map[string]string, obser
 
 // UpdateStatus use reconciled objects to update component status
 func (s *SQLProxy) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	handlerDone(s.events, rsrc, "SQLProxy", reconciled, err)
+	r := rsrc.(*alpha1.AirflowBase)
+	common.UpdateComponentCondition(&r.Status.Meta, "SQLProxy", r.Spec.SQLProxy != nil, reconciled, err)
 	return updateStatus(rsrc, reconciled, err)
 }
 
@@ -346,7 +386,23 @@ func (s *AirflowBase) Objects(rsrc interface{}, rsrclabels map[string]string, ob
 		Build()
 }
 
+// Finalize forgets the readiness seen for the objects of the base and its
+// reconcile series
+func (s *AirflowBase) Finalize(rsrc interface{}, observed, dependent []reconciler.Object) error {
+	r := rsrc.(*alpha1.AirflowBase)
+	s.events.Forget(r)
+	metrics.Forget(common.KindAirflowBase, r)
+	finalizer.RemoveStandard(r)
+	return nil
+}
+
 // UpdateStatus use reconciled objects to update component status
 func (s *AirflowBase) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	handlerDone(s.events, rsrc, "AirflowBase", reconciled, err)
+	// the handlers before this one all reconciled the spec without errors
+	if err == nil {
+		r := rsrc.(*alpha1.AirflowBase)
+		r.Status.ObservedGeneration = r.Generation
+	}
 	return updateStatus(rsrc, reconciled, err)
 }
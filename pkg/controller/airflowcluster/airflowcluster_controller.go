@@ -17,15 +17,34 @@ package airflowcluster
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	app "github.com/kubernetes-sigs/application/pkg/apis/app/v1beta1"
+	"hash"
 	alpha1 "k8s.io/airflow-operator/pkg/apis/airflow/v1alpha1"
 	"k8s.io/airflow-operator/pkg/controller/application"
 	"k8s.io/airflow-operator/pkg/controller/common"
+	"k8s.io/airflow-operator/pkg/metrics"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
 	policyv1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"log"
+	"net/url"
+	"reflect"
 	"sigs.k8s.io/controller-reconciler/pkg/finalizer"
 	gr "sigs.k8s.io/controller-reconciler/pkg/genericreconciler"
 	"sigs.k8s.io/controller-reconciler/pkg/reconciler"
@@ -33,7 +52,12 @@ import (
 	"sigs.k8s.io/controller-reconciler/pkg/reconciler/manager/gcp/redis"
 	"sigs.k8s.io/controller-reconciler/pkg/reconciler/manager/k8s"
 	"sigs.k8s.io/controller-reconciler/pkg/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	"sort"
 	"strconv"
 	"strings"
@@ -41,12 +65,144 @@ import (
 )
 
 const (
-	afk             = "AIRFLOW__KUBERNETES__"
-	afc             = "AIRFLOW__CORE__"
-	gitSyncDestDir  = "gitdags"
-	gCSSyncDestDir  = "dags"
-	airflowHome     = "/usr/local/airflow"
-	airflowDagsBase = airflowHome + "/dags/"
+	afk              = "AIRFLOW__KUBERNETES__"
+	afc              = "AIRFLOW__CORE__"
+	gitSyncDestDir   = "gitdags"
+	gCSSyncDestDir   = "dags"
+	s3SyncDestDir    = "s3dags"
+	s3SyncInterval   = "60"
+	gitSecretDir     = "/etc/git-secret"
+	gitSecretVolName = "git-secret"
+	gitSyncGroup     = 65533
+	airflowHome      = "/usr/local/airflow"
+	airflowDagsBase  = airflowHome + "/dags/"
+
+	statsdPort        = "9125"
+	statsdMetricsPort = "9102"
+	statsdMappings    = "mappings.yaml"
+
+	dagCheckInterval             = time.Minute
+	dagJobPollInterval           = 15 * time.Second
+	conditionDagRevisionSkew     = "DagRevisionSkew"
+	conditionDagValidationFailed = common.EventDagValidationFailed
+	schedulerCheckInterval       = 2 * time.Minute
+	schedulerRetryInterval       = 30 * time.Second
+	schedulerPollInterval        = 15 * time.Second
+	configPendingInterval        = 30 * time.Second
+)
+
+// schedulerStatsScript prints the DAG, run and heartbeat statistics of the
+// scheduler read from the metadata DB
+const schedulerStatsScript = `
+import json
+from datetime import timedelta
+from airflow import models, settings
+try:
+    from airflow.jobs.base_job import BaseJob
+except ImportError:
+    from airflow.jobs import BaseJob
+from airflow.utils import timezone
+from airflow.utils.state import State
+
+now = timezone.utcnow()
+session = settings.Session()
+dags = session.query(models.DagModel).filter(models.DagModel.is_active == True)
+runs = session.query(models.DagRun)
+tis = session.query(models.TaskInstance)
+result = {
+    'dagCount': dags.count(),
+    'pausedDagCount': dags.filter(models.DagModel.is_paused == True).count(),
+    'runCount': runs.count(),
+    'runningRunCount': runs.filter(models.DagRun.state == State.RUNNING).count(),
+    'queuedRunCount': runs.filter(models.DagRun.state == State.QUEUED).count(),
+    'failedTaskCount': tis.filter(models.TaskInstance.state == State.FAILED,
+                                  models.TaskInstance.end_date >= now - timedelta(hours=1)).count(),
+}
+job = session.query(BaseJob).filter(BaseJob.job_type == 'SchedulerJob').order_by(BaseJob.latest_heartbeat.desc()).first()
+if job and job.latest_heartbeat:
+    result['lastHeartbeat'] = job.latest_heartbeat.isoformat()
+    result['heartbeatAge'] = int((now - job.latest_heartbeat).total_seconds())
+print(json.dumps(result))
+`
+
+type schedulerStats struct {
+	DagCount        int32  `json:"dagCount"`
+	PausedDagCount  int32  `json:"pausedDagCount"`
+	RunCount        int32  `json:"runCount"`
+	RunningRunCount int32  `json:"runningRunCount"`
+	QueuedRunCount  int32  `json:"queuedRunCount"`
+	FailedTaskCount int32  `json:"failedTaskCount"`
+	LastHeartbeat   string `json:"lastHeartbeat"`
+	HeartbeatAge    int64  `json:"heartbeatAge"`
+}
+
+const (
+	airflowPluginsBase   = airflowHome + "/plugins/"
+	pluginsVolName       = "plugins-data"
+	pluginsSecretVolName = "plugins-git-secret"
+	requirementsDir      = airflowHome + "/requirements"
+	requirementsVolName  = "requirements"
+)
+
+const (
+	remoteLogConnID  = "remote_logs"
+	logSecretDir     = "/etc/airflow-log-secret"
+	logSecretVolName = "log-secret"
+)
+
+const (
+	airflowLogsDir = airflowHome + "/logs"
+	logVolName     = "logs-data"
+)
+
+const (
+	airflowCfg           = "airflow.cfg"
+	airflowCfgVolName    = "airflow-config"
+	annotationConfigHash = "airflow.k8s.io/config-hash"
+	// annotationBackendProtocol has ingress-nginx talk https to the UI
+	annotationBackendProtocol = "nginx.ingress.kubernetes.io/backend-protocol"
+)
+
+var serviceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+
+const (
+	fernetKeyKey                     = "fernet-key"
+	webserverSecretKeyKey            = "secret-key"
+	conditionFernetKeyRotationFailed = common.EventFernetKeyRotationFailed
+	annotationFernetKeyRotation      = "airflow.k8s.io/fernet-key-rotation"
+)
+
+const (
+	webserverConfig        = "webserver_config.py"
+	webserverConfigVolName = "webserver-config"
+)
+
+const (
+	uiTLSDir        = "/etc/airflow-ui-tls"
+	uiTLSVolName    = "ui-tls"
+	certValidity    = 365 * 24 * time.Hour
+	certRenewBefore = 30 * 24 * time.Hour
+)
+
+// createAdminArgs are the arguments of the command creating the Admin user
+// of a password logins UI. It leaves an existing user unchanged.
+const createAdminArgs = `-r Admin -u "$ADMIN_USERNAME" \
+  -e "${ADMIN_EMAIL:-$ADMIN_USERNAME@localhost}" -f Admin -l User -p "$ADMIN_PASSWORD"`
+
+// createAdmin returns the shell command creating the Admin user with the
+// cli of the airflow version
+func createAdmin(version string) string {
+	// the cli commands are grouped from airflow 2
+	if !strings.HasPrefix(version, "1.") {
+		return "exec airflow users create " + createAdminArgs
+	}
+	return "exec airflow create_user " + createAdminArgs
+}
+
+const (
+	secretsBackendDir     = airflowHome + "/secrets-backend"
+	secretsBackendVolName = "secrets-backend"
+	secretsBackendIndex   = "index.json"
 )
 
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
@@ -57,45 +213,89 @@ const (
 // +kubebuilder:rbac:groups=app.k8s.io,resources=applications,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=,resources=pods,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=,resources=events,verbs=create;patch
+// pods/exec is only used by the DAG probe of the running components
+// +kubebuilder:rbac:groups=,resources=pods/exec,verbs=create
+// +kubebuilder:rbac:groups=extensions,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
 
 // Add creates a new AirflowBase Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
 	r := newReconciler(mgr)
-	return r.Controller(nil)
+	c, err := controller.New("airflowcluster-application", mgr, controller.Options{
+		Reconciler: metrics.NewReconciler(common.KindAirflowCluster, r),
+	})
+	if err != nil {
+		return err
+	}
+	err = c.Watch(&source.Kind{Type: &alpha1.AirflowCluster{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+	// the secrets backend index follows the labeled secrets
+	return c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: backendClusters(mgr.GetClient()),
+	})
 }
 
+// newReconciler builds the reconciler of a manager. The handlers of a
+// manager share the events recorded on its AirflowClusters.
 func newReconciler(mgr manager.Manager) *gr.Reconciler {
+	events := common.NewEvents(mgr.GetRecorder("airflowcluster-controller"))
 	return gr.
 		WithManager(mgr).
 		WithResourceManager(redis.Getter(context.TODO())).
 		For(&alpha1.AirflowCluster{}, alpha1.SchemeGroupVersion).
-		Using(&UI{}).
-		Using(&Redis{}).
-		Using(&MemoryStore{}).
-		Using(&Flower{}).
-		Using(&Scheduler{}).
-		Using(&Worker{}).
-		Using(&Cluster{}).
-		WithErrorHandler(handleError).
+		Using(&Keys{client: mgr.GetClient(), events: events}).
+		Using(&DagValidation{client: mgr.GetClient(), events: events}).
+		Using(&UI{client: mgr.GetClient(), events: events}).
+		Using(&Redis{events: events}).
+		Using(&MemoryStore{events: events}).
+		Using(&Flower{client: mgr.GetClient(), events: events}).
+		Using(&Scheduler{client: mgr.GetClient(), events: events}).
+		Using(&Worker{client: mgr.GetClient(), events: events}).
+		Using(&Logs{events: events}).
+		Using(&Metrics{events: events}).
+		Using(&SecretsBackend{client: mgr.GetClient(), events: events}).
+		Using(&Cluster{client: mgr.GetClient(), exec: common.NewPodExecutor(mgr.GetConfig()), db: common.NewMetadataDB(mgr.GetClient()), events: events}).
+		WithErrorHandler(errorHandler(events)).
 		WithValidator(validate).
 		WithDefaulter(applyDefaults).
 		RegisterSchemeBuilder(app.SchemeBuilder).
 		Build()
 }
 
-func handleError(resource interface{}, err error, kind string) {
-	ac := resource.(*alpha1.AirflowCluster)
-	if err != nil {
-		ac.Status.SetError("ErrorSeen", err.Error())
-	} else {
-		ac.Status.ClearError()
+// errorHandler returns the error handler of the reconciler, it records the
+// errors with events
+func errorHandler(events *common.Events) func(interface{}, error, string) {
+	return func(resource interface{}, err error, errkind string) {
+		ac := resource.(*alpha1.AirflowCluster)
+		if err != nil {
+			metrics.ReconcileError(common.KindAirflowCluster, ac, errkind)
+			events.Error(ac, err, errkind)
+			ac.Status.SetError("ErrorSeen", err.Error())
+		} else {
+			ac.Status.ClearError()
+		}
 	}
 }
 
+// handlerDone records the reconcile of a handler in the operator metrics
+// and the events about the objects it reconciled
+func handlerDone(events *common.Events, rsrc interface{}, handler string, reconciled []reconciler.Object, err error) {
+	metrics.HandlerDone(common.KindAirflowCluster, rsrc.(*alpha1.AirflowCluster), handler, err)
+	events.Reconciled(rsrc.(*alpha1.AirflowCluster), reconciled)
+}
+
 func validate(resource interface{}) error {
 	ac := resource.(*alpha1.AirflowCluster)
 	return ac.Validate()
@@ -107,25 +307,74 @@ func applyDefaults(resource interface{}) {
 }
 
 // Cluster - interface to handle airflowbase
-type Cluster struct{}
+type Cluster struct {
+	client client.Client
+	exec   *common.PodExecutor
+	db     *common.MetadataDB
+	events *common.Events
+}
 
 // Redis - interface to handle redis
-type Redis struct{}
+type Redis struct {
+	events *common.Events
+}
 
 // Flower - interface to handle flower
-type Flower struct{}
+type Flower struct {
+	client client.Client
+	events *common.Events
+}
 
 // Scheduler - interface to handle scheduler
-type Scheduler struct{}
+type Scheduler struct {
+	client client.Client
+	events *common.Events
+}
 
 // Worker - interface to handle worker
-type Worker struct{}
+type Worker struct {
+	client client.Client
+	events *common.Events
+}
 
 // UI - interface to handle ui
-type UI struct{}
+type UI struct {
+	client client.Client
+	events *common.Events
+}
 
 // MemoryStore - interface to handle memorystore
-type MemoryStore struct{}
+type MemoryStore struct {
+	events *common.Events
+}
+
+// Logs - interface to handle the shared log volume
+type Logs struct {
+	events *common.Events
+}
+
+// Keys - interface to handle the Fernet key and webserver secret key
+type Keys struct {
+	client client.Client
+	events *common.Events
+}
+
+// Metrics - interface to handle the statsd-exporter
+type Metrics struct {
+	events *common.Events
+}
+
+// SecretsBackend - interface to handle the secrets backend index and access
+type SecretsBackend struct {
+	client client.Client
+	events *common.Events
+}
+
+// DagValidation - interface to handle the DAG validation gate
+type DagValidation struct {
+	client client.Client
+	events *common.Events
+}
 
 // --------------- common functions -------------------------
 
@@ -140,6 +389,18 @@ func envFromSecret(name string, key string) *corev1.EnvVarSource {
 	}
 }
 
+// sqlConn returns the sql_alchemy_conn of the metadata DB
+func sqlConn(r *alpha1.AirflowCluster, base *alpha1.AirflowBase, password string) string {
+	sqlSvcName := common.RsrcName(r.Spec.AirflowBaseRef.Name, common.ValueAirflowComponentSQL, "")
+	dbPrefix := "mysql"
+	port := "3306"
+	if base.Spec.Postgres != nil {
+		dbPrefix = "postgresql+psycopg2"
+		port = "5432"
+	}
+	return dbPrefix + "://" + r.Spec.Scheduler.DBUser + ":" + password + "@" + sqlSvcName + ":" + port + "/" + r.Spec.Scheduler.DBName
+}
+
 // IsPostgres return true for postgres
 func IsPostgres(s *alpha1.AirflowBaseSpec) bool {
 	postgres := false
@@ -157,12 +418,189 @@ func updateSts(o *reconciler.Object, v interface{}) (*appsv1.StatefulSet, *commo
 	sts := o.Obj.(*k8s.Object).Obj.(*appsv1.StatefulSet)
 	sts.Spec.Template.Spec.Containers[0].Env = getAirflowEnv(r.Cluster, sts.Name, r.Base)
 	addAirflowContainers(r.Cluster, sts)
+	if r.ConfigHash != "" {
+		// roll the pods when a consumed configmap or secret changes
+		if sts.Spec.Template.Annotations == nil {
+			sts.Spec.Template.Annotations = map[string]string{}
+		}
+		sts.Spec.Template.Annotations[annotationConfigHash] = r.ConfigHash
+	}
 	return sts, r
 }
 
+// consumedSecrets returns the names of the secrets used by the airflow containers
+func consumedSecrets(r *alpha1.AirflowCluster, component string) []string {
+	sp := r.Spec
+	names := []string{
+		common.RsrcName(r.Name, common.ValueAirflowComponentUI, ""),
+		common.RsrcName(r.Name, common.ValueAirflowComponentKeys, ""),
+	}
+	if sp.Executor == alpha1.ExecutorCelery && sp.MemoryStore == nil && sp.Redis != nil &&
+		(sp.Redis.RedisHost == "" || sp.Redis.RedisPassword) {
+		names = append(names, common.RsrcName(r.Name, common.ValueAirflowComponentRedis, ""))
+	}
+	for _, se := range sp.Config.AirflowSecretEnv {
+		names = append(names, se.Secret)
+	}
+	if component == common.ValueAirflowComponentUI && sp.UI != nil && sp.UI.TLS != nil {
+		names = append(names, uiTLSSecret(r))
+	}
+	if component == common.ValueAirflowComponentUI && sp.UI != nil && sp.UI.Auth != nil {
+		auth := sp.UI.Auth
+		if auth.Password != nil {
+			names = append(names, auth.Password.AdminSecretRef.Name)
+		}
+		if auth.LDAP != nil && auth.LDAP.BindSecretRef != nil {
+			names = append(names, auth.LDAP.BindSecretRef.Name)
+		}
+		if auth.OAuth != nil {
+			names = append(names, auth.OAuth.ClientSecretRef.Name)
+		}
+	}
+	gits := []*alpha1.GitSpec{}
+	storages := []*alpha1.StorageSpec{}
+	if sp.DAGs != nil {
+		gits = append(gits, sp.DAGs.Git)
+		storages = append(storages, sp.DAGs.Storage)
+	}
+	if sp.Plugins != nil {
+		gits = append(gits, sp.Plugins.Git)
+		storages = append(storages, sp.Plugins.Storage)
+	}
+	if sp.Logging != nil {
+		storages = append(storages, &sp.Logging.Storage)
+	}
+	for _, git := range gits {
+		if git != nil && git.CredSecretRef != nil {
+			names = append(names, git.CredSecretRef.Name)
+		}
+		if git != nil && git.SSHSecretRef != nil {
+			names = append(names, git.SSHSecretRef.Name)
+		}
+	}
+	for _, storage := range storages {
+		if storage != nil && storage.SecretRef != nil {
+			names = append(names, storage.SecretRef.Name)
+		}
+	}
+	return names
+}
+
+func hashData(h hash.Hash, kind, name string, data map[string][]byte) {
+	keys := []string{}
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h.Write([]byte(kind + "/" + name + "\x00"))
+	for _, k := range keys {
+		h.Write([]byte(k + "\x00"))
+		h.Write(data[k])
+		h.Write([]byte("\x00"))
+	}
+}
+
+// configHash returns a hash of the airflow configmaps rendered from the spec
+// and of the secrets consumed by the airflow containers of component. The
+// configmaps are rendered here rather than read back so that components
+// reconciled before the Scheduler roll together with it. The name of a
+// consumed secret that does not exist yet is returned instead of a hash.
+func configHash(c client.Client, r *alpha1.AirflowCluster, component string) (string, string, error) {
+	h := sha256.New()
+	configmaps := map[string]string{
+		common.RsrcName(r.Name, common.ValueAirflowComponentScheduler, ""): "airflow-configmap.yaml",
+	}
+	if component == common.ValueAirflowComponentUI && r.Spec.UI != nil && r.Spec.UI.Auth != nil {
+		configmaps[common.RsrcName(r.Name, common.ValueAirflowComponentUI, "")] = "webserver-configmap.yaml"
+	}
+	cmNames := []string{}
+	for cmName := range configmaps {
+		cmNames = append(cmNames, cmName)
+	}
+	sort.Strings(cmNames)
+	for _, cmName := range cmNames {
+		// the sql_alchemy_conn of task pods is left out, its password is in a hashed secret
+		value := &common.TemplateValue{Name: cmName, Namespace: r.Namespace, Cluster: r}
+		if r.Spec.Logging != nil {
+			value.LogConn = remoteLogConn(r.Spec.Logging)
+		}
+		o, err := k8s.ObjectFromFile(common.TemplatePath+configmaps[cmName], value, &corev1.ConfigMapList{})
+		if err != nil {
+			return "", "", err
+		}
+		cm := o.Obj.(*k8s.Object).Obj.(*corev1.ConfigMap)
+		if cfg, ok := cm.Data[airflowCfg]; ok {
+			cm.Data[airflowCfg] = common.MergeConfig(cfg, r.Spec.Config.Sections)
+		}
+		data := map[string][]byte{}
+		for k, v := range cm.Data {
+			data[k] = []byte(v)
+		}
+		hashData(h, "configmap", cmName, data)
+	}
+
+	names := consumedSecrets(r, component)
+	sort.Strings(names)
+	for i, name := range names {
+		if i > 0 && names[i-1] == name {
+			continue
+		}
+		secret := &corev1.Secret{}
+		err := c.Get(context.TODO(), client.ObjectKey{Namespace: r.Namespace, Name: name}, secret)
+		if apierrors.IsNotFound(err) {
+			return "", name, nil
+		} else if err != nil {
+			return "", "", err
+		}
+		hashData(h, "secret", name, secret.Data)
+	}
+	return hex.EncodeToString(h.Sum(nil)), "", nil
+}
+
+// setConfigHash sets the config hash of the component StatefulSet. While a
+// consumed secret is missing the hash of the observed StatefulSet is kept so
+// that its pods are not rolled, a new StatefulSet is created without one.
+func setConfigHash(c client.Client, r *alpha1.AirflowCluster, component string, observed []reconciler.Object, v *common.TemplateValue) error {
+	hash, missing, err := configHash(c, r, component)
+	if err != nil {
+		return err
+	}
+	if missing != "" {
+		for _, o := range observed {
+			if sts, ok := o.Obj.(*k8s.Object).Obj.(*appsv1.StatefulSet); ok {
+				hash = sts.Spec.Template.Annotations[annotationConfigHash]
+			}
+		}
+	}
+	v.ConfigHash = hash
+	return nil
+}
+
+// configRequeue marks the component not ready and returns the interval to
+// check again while a secret consumed by its StatefulSet is missing
+func configRequeue(c client.Client, r *alpha1.AirflowCluster, component, condition string, err error) time.Duration {
+	if err != nil {
+		return 0
+	}
+	_, missing, err := configHash(c, r, component)
+	if err != nil || missing == "" {
+		return 0
+	}
+	r.Status.Meta.ClearCondition(common.ComponentCondition(condition), common.ReasonSecretMissing,
+		fmt.Sprintf("secret %s is missing, the pods are rolled once it exists", missing))
+	return configPendingInterval
+}
+
 func templateValue(r *alpha1.AirflowCluster, dependent []reconciler.Object, component string, label, selector, ports map[string]string) *common.TemplateValue {
 	b := k8s.GetItem(dependent, &alpha1.AirflowBase{}, r.Spec.AirflowBaseRef.Name, r.Namespace)
 	base := b.(*alpha1.AirflowBase)
+	nfsServer := ""
+	if r.Spec.LogVolume != nil && r.Spec.LogVolume.NFS {
+		nfsSvcName := common.RsrcName(r.Spec.AirflowBaseRef.Name, common.ValueAirflowComponentNFS, "")
+		if svc := k8s.GetItem(dependent, &corev1.Service{}, nfsSvcName, r.Namespace); svc != nil {
+			nfsServer = svc.(*corev1.Service).Spec.ClusterIP
+		}
+	}
 	return &common.TemplateValue{
 		Name:       common.RsrcName(r.Name, component, ""),
 		Namespace:  r.Namespace,
@@ -173,17 +611,206 @@ func templateValue(r *alpha1.AirflowCluster, dependent []reconciler.Object, comp
 		Labels:     label,
 		Selector:   selector,
 		Ports:      ports,
+		NFSServer:  nfsServer,
+	}
+}
+
+// logVolume returns the shared volume and the mount for the logs folder.
+// Clusters sharing the NFS server of a base use a subdirectory each.
+func logVolume(r *common.TemplateValue) (corev1.Volume, corev1.VolumeMount) {
+	vol := corev1.Volume{Name: logVolName}
+	mount := corev1.VolumeMount{Name: logVolName, MountPath: airflowLogsDir}
+	if r.Cluster.Spec.LogVolume.NFS {
+		vol.NFS = &corev1.NFSVolumeSource{Server: r.NFSServer, Path: "/"}
+		mount.SubPath = r.Cluster.Name
+	} else {
+		vol.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{
+			ClaimName: common.RsrcName(r.Cluster.Name, common.ValueAirflowComponentLogs, ""),
+		}
+	}
+	return vol, mount
+}
+
+// addLogVolume mounts the shared volume at the logs folder of the airflow container
+func addLogVolume(r *common.TemplateValue, spec *corev1.PodSpec) {
+	if r.Cluster.Spec.LogVolume == nil {
+		return
 	}
+	vol, mount := logVolume(r)
+	spec.Volumes = append(spec.Volumes, vol)
+	spec.Containers[0].VolumeMounts = append(spec.Containers[0].VolumeMounts, mount)
+	// the volume root is owned by root, let the airflow user write to it
+	root := int64(0)
+	spec.InitContainers = append(spec.InitContainers, corev1.Container{
+		Name:            "logs-perms",
+		Image:           spec.Containers[0].Image,
+		ImagePullPolicy: spec.Containers[0].ImagePullPolicy,
+		Command:         []string{"chmod", "1777", airflowLogsDir},
+		SecurityContext: &corev1.SecurityContext{RunAsUser: &root},
+		VolumeMounts:    []corev1.VolumeMount{mount},
+	})
 }
 
 func addAirflowContainers(r *alpha1.AirflowCluster, ss *appsv1.StatefulSet) {
 	if r.Spec.DAGs != nil {
-		init, dc := dagContainer(r.Spec.DAGs, "dags-data")
+		dags := r.Spec.DAGs
+		if validatedGit(r) != nil {
+			// pin git-sync to the last revision that passed validation
+			dags = dags.DeepCopy()
+			dags.Git.Rev = gitRev(r)
+		}
+		init, dc := dagContainer(dags, "dags-data")
 		if init {
 			ss.Spec.Template.Spec.InitContainers = append(ss.Spec.Template.Spec.InitContainers, dc)
 		} else {
 			ss.Spec.Template.Spec.Containers = append(ss.Spec.Template.Spec.Containers, dc)
 		}
+		addGitSecretVolume(&ss.Spec.Template.Spec, dags.Git, gitSecretVolName)
+	}
+	addPlugins(r, &ss.Spec.Template.Spec)
+	addLogSecretVolume(r, &ss.Spec.Template.Spec)
+	addAirflowConfig(r, &ss.Spec.Template.Spec)
+	addSecretsBackend(r, &ss.Spec.Template.Spec)
+}
+
+// addSecretsBackend runs the pod as the service account allowed to read the
+// indexed secrets and puts the backend module on the python path
+func addSecretsBackend(r *alpha1.AirflowCluster, spec *corev1.PodSpec) {
+	if r.Spec.SecretsBackend == nil {
+		return
+	}
+	name := common.RsrcName(r.Name, common.ValueAirflowComponentSecrets, "")
+	spec.ServiceAccountName = name
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name: secretsBackendVolName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			},
+		},
+	})
+	// no subPath, the kubelet has to refresh the index when secrets come and go
+	spec.Containers[0].VolumeMounts = append(spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      secretsBackendVolName,
+		MountPath: secretsBackendDir,
+		ReadOnly:  true,
+	})
+	addPythonPath(&spec.Containers[0], secretsBackendDir)
+}
+
+// addPythonPath appends dir to the PYTHONPATH of the container
+func addPythonPath(c *corev1.Container, dir string) {
+	for i := range c.Env {
+		if c.Env[i].Name == "PYTHONPATH" {
+			c.Env[i].Value += ":" + dir
+			return
+		}
+	}
+	c.Env = append(c.Env, corev1.EnvVar{Name: "PYTHONPATH", Value: dir})
+}
+
+// addAirflowConfig mounts the managed airflow.cfg over the one in the image
+func addAirflowConfig(r *alpha1.AirflowCluster, spec *corev1.PodSpec) {
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name: airflowCfgVolName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: common.RsrcName(r.Name, common.ValueAirflowComponentScheduler, ""),
+				},
+				Items: []corev1.KeyToPath{{Key: airflowCfg, Path: airflowCfg}},
+			},
+		},
+	})
+	spec.Containers[0].VolumeMounts = append(spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      airflowCfgVolName,
+		MountPath: airflowHome + "/" + airflowCfg,
+		SubPath:   airflowCfg,
+		ReadOnly:  true,
+	})
+}
+
+func addGitSecretVolume(spec *corev1.PodSpec, git *alpha1.GitSpec, volName string) {
+	if git == nil || git.SSHSecretRef == nil {
+		return
+	}
+	vol := gitSecretVolume(git)
+	vol.Name = volName
+	spec.Volumes = append(spec.Volumes, vol)
+	if spec.SecurityContext == nil {
+		spec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+	if spec.SecurityContext.FSGroup == nil {
+		gid := int64(gitSyncGroup)
+		spec.SecurityContext.FSGroup = &gid
+	}
+}
+
+// pluginsFolder returns the folder the plugins are synced into
+func pluginsFolder(s *alpha1.PluginsSpec) string {
+	folder := airflowPluginsBase
+	if s.Git != nil {
+		folder = airflowPluginsBase + gitSyncDestDir + "/" + s.PluginsSubdir
+	} else if s.GCS != nil {
+		folder = airflowPluginsBase + gCSSyncDestDir + "/" + s.PluginsSubdir
+	} else if s.Storage != nil {
+		folder = airflowPluginsBase + s3SyncDestDir + "/" + s.PluginsSubdir
+	}
+	return folder
+}
+
+// addPlugins adds the plugins sync container and the pip install init
+// container to the pod. The first container is the airflow container.
+func addPlugins(r *alpha1.AirflowCluster, spec *corev1.PodSpec) {
+	p := r.Spec.Plugins
+	if p == nil {
+		return
+	}
+	if p.Git != nil || p.GCS != nil || p.Storage != nil {
+		init, pc := dagContainer(&alpha1.DagSpec{Git: p.Git, GCS: p.GCS, Storage: p.Storage}, pluginsVolName)
+		// the names and ports must not clash with the DAG sync container
+		pc.Name = "plugins-" + pc.Name
+		pc.Ports = nil
+		for i := range pc.VolumeMounts {
+			if pc.VolumeMounts[i].Name == gitSecretVolName {
+				pc.VolumeMounts[i].Name = pluginsSecretVolName
+			}
+		}
+		if init {
+			spec.InitContainers = append(spec.InitContainers, pc)
+		} else {
+			spec.Containers = append(spec.Containers, pc)
+		}
+		addGitSecretVolume(spec, p.Git, pluginsSecretVolName)
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name:         pluginsVolName,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+		spec.Containers[0].VolumeMounts = append(spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      pluginsVolName,
+			MountPath: airflowPluginsBase,
+		})
+		spec.Containers[0].Env = append(spec.Containers[0].Env, corev1.EnvVar{
+			Name:  afc + "PLUGINS_FOLDER",
+			Value: pluginsFolder(p),
+		})
+	}
+	if len(p.Requirements) != 0 {
+		mount := corev1.VolumeMount{Name: requirementsVolName, MountPath: requirementsDir}
+		spec.InitContainers = append(spec.InitContainers, corev1.Container{
+			Name:            "pip-install",
+			Image:           spec.Containers[0].Image,
+			ImagePullPolicy: spec.Containers[0].ImagePullPolicy,
+			Command:         []string{"pip", "install", "--no-cache-dir", "--target", requirementsDir},
+			Args:            p.Requirements,
+			VolumeMounts:    []corev1.VolumeMount{mount},
+		})
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name:         requirementsVolName,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+		spec.Containers[0].VolumeMounts = append(spec.Containers[0].VolumeMounts, mount)
+		addPythonPath(&spec.Containers[0], requirementsDir)
 	}
 }
 
@@ -252,37 +879,21 @@ func dependantResources(i interface{}) []reconciler.Object {
 	r := i.(*alpha1.AirflowCluster)
 	rsrc := []reconciler.Object{}
 	rsrc = append(rsrc, k8s.ReferredItem(&alpha1.AirflowBase{}, r.Spec.AirflowBaseRef.Name, r.Namespace))
-	return rsrc
-}
-
-func getAirflowPrometheusEnv(r *alpha1.AirflowCluster, base *alpha1.AirflowBase) []corev1.EnvVar {
-	sqlSvcName := common.RsrcName(r.Spec.AirflowBaseRef.Name, common.ValueAirflowComponentSQL, "")
-	sqlSecret := common.RsrcName(r.Name, common.ValueAirflowComponentUI, "")
-	ap := "AIRFLOW_PROMETHEUS_"
-	apd := ap + "DATABASE_"
-	backend := "mysql"
-	port := "3306"
-	if IsPostgres(&base.Spec) {
-		backend = "postgres"
-		port = "5432"
-	}
-	env := []corev1.EnvVar{
-		{Name: ap + "LISTEN_ADDR", Value: ":9112"},
-		{Name: apd + "BACKEND", Value: backend},
-		{Name: apd + "HOST", Value: sqlSvcName},
-		{Name: apd + "PORT", Value: port},
-		{Name: apd + "USER", Value: r.Spec.Scheduler.DBUser},
-		{Name: apd + "PASSWORD", ValueFrom: envFromSecret(sqlSecret, "password")},
-		{Name: apd + "NAME", Value: r.Spec.Scheduler.DBName},
+	if r.Spec.LogVolume != nil && r.Spec.LogVolume.NFS {
+		// pods mount the export by the service IP, the node cannot resolve service names
+		nfsSvcName := common.RsrcName(r.Spec.AirflowBaseRef.Name, common.ValueAirflowComponentNFS, "")
+		rsrc = append(rsrc, k8s.ReferredItem(&corev1.Service{}, nfsSvcName, r.Namespace))
 	}
-	return env
+	return rsrc
 }
 
 func getAirflowEnv(r *alpha1.AirflowCluster, saName string, base *alpha1.AirflowBase) []corev1.EnvVar {
 	sp := r.Spec
 	sqlSvcName := common.RsrcName(sp.AirflowBaseRef.Name, common.ValueAirflowComponentSQL, "")
 	sqlSecret := common.RsrcName(r.Name, common.ValueAirflowComponentUI, "")
+	keysSecret := common.RsrcName(r.Name, common.ValueAirflowComponentKeys, "")
 	schedulerConfigmap := common.RsrcName(r.Name, common.ValueAirflowComponentScheduler, "")
+	knownHostsConfigmap := common.RsrcName(r.Name, common.ValueAirflowComponentScheduler, "-known-hosts")
 	redisSecret := ""
 	redisSvcName := ""
 	if sp.MemoryStore == nil {
@@ -295,6 +906,8 @@ func getAirflowEnv(r *alpha1.AirflowCluster, saName string, base *alpha1.Airflow
 			dagFolder = airflowDagsBase + gitSyncDestDir + "/" + sp.DAGs.DagSubdir
 		} else if sp.DAGs.GCS != nil {
 			dagFolder = airflowDagsBase + gCSSyncDestDir + "/" + sp.DAGs.DagSubdir
+		} else if sp.DAGs.Storage != nil {
+			dagFolder = airflowDagsBase + s3SyncDestDir + "/" + sp.DAGs.DagSubdir
 		}
 	}
 	dbType := "mysql"
@@ -304,11 +917,14 @@ func getAirflowEnv(r *alpha1.AirflowCluster, saName string, base *alpha1.Airflow
 	env := []corev1.EnvVar{
 		{Name: "EXECUTOR", Value: sp.Executor},
 		{Name: "SQL_PASSWORD", ValueFrom: envFromSecret(sqlSecret, "password")},
+		{Name: afc + "SQL_ALCHEMY_CONN", Value: sqlConn(r, base, "$(SQL_PASSWORD)")},
 		{Name: afc + "DAGS_FOLDER", Value: dagFolder},
 		{Name: "SQL_HOST", Value: sqlSvcName},
 		{Name: "SQL_USER", Value: sp.Scheduler.DBUser},
 		{Name: "SQL_DB", Value: sp.Scheduler.DBName},
 		{Name: "DB_TYPE", Value: dbType},
+		{Name: afc + "FERNET_KEY", ValueFrom: envFromSecret(keysSecret, fernetKeyKey)},
+		{Name: "AIRFLOW__WEBSERVER__SECRET_KEY", ValueFrom: envFromSecret(keysSecret, webserverSecretKeyKey)},
 	}
 	if sp.Executor == alpha1.ExecutorK8s {
 		env = append(env, []corev1.EnvVar{
@@ -332,6 +948,10 @@ func getAirflowEnv(r *alpha1.AirflowCluster, saName string, base *alpha1.Airflow
 				// git_sync_root = /git
 				// git_sync_dest = repo
 			}...)
+			if rev := gitRev(r); rev != "" {
+				// task pods sync the same commit as the scheduler and not the branch head
+				env = append(env, corev1.EnvVar{Name: afk + "GIT_SYNC_REV", Value: rev})
+			}
 			if sp.DAGs.Git.CredSecretRef != nil {
 				env = append(env, []corev1.EnvVar{
 					{Name: "GIT_PASSWORD",
@@ -339,19 +959,29 @@ func getAirflowEnv(r *alpha1.AirflowCluster, saName string, base *alpha1.Airflow
 					{Name: "GIT_USER", Value: sp.DAGs.Git.User},
 				}...)
 			}
+			if sp.DAGs.Git.SSHSecretRef != nil {
+				env = append(env, []corev1.EnvVar{
+					{Name: afk + "GIT_SSH_KEY_SECRET_NAME", Value: sp.DAGs.Git.SSHSecretRef.Name},
+					{Name: afk + "GIT_SSH_KNOWN_HOSTS_CONFIGMAP_NAME", Value: knownHostsConfigmap},
+				}...)
+			}
 		}
 		// dags_in_image = False
 		// dags_volume_subpath =
 		// dags_volume_claim =
 	}
 	if sp.Executor == alpha1.ExecutorCelery {
+		redisAuth := ""
+		redisPort := "6379"
 		if sp.MemoryStore != nil {
+			redisPort = strconv.FormatInt(sp.MemoryStore.Status.Port, 10)
 			env = append(env,
 				[]corev1.EnvVar{
 					{Name: "REDIS_HOST", Value: sp.MemoryStore.Status.Host},
-					{Name: "REDIS_PORT", Value: strconv.FormatInt(sp.MemoryStore.Status.Port, 10)},
+					{Name: "REDIS_PORT", Value: redisPort},
 				}...)
 		} else if r.Spec.Redis.RedisHost == "" {
+			redisAuth = ":$(REDIS_PASSWORD)@"
 			env = append(env,
 				[]corev1.EnvVar{
 					{Name: "REDIS_PASSWORD",
@@ -359,12 +989,14 @@ func getAirflowEnv(r *alpha1.AirflowCluster, saName string, base *alpha1.Airflow
 					{Name: "REDIS_HOST", Value: redisSvcName},
 				}...)
 		} else {
+			redisPort = r.Spec.Redis.RedisPort
 			env = append(env,
 				[]corev1.EnvVar{
 					{Name: "REDIS_HOST", Value: r.Spec.Redis.RedisHost},
-					{Name: "REDIS_PORT", Value: r.Spec.Redis.RedisPort},
+					{Name: "REDIS_PORT", Value: redisPort},
 				}...)
 			if r.Spec.Redis.RedisPassword == true {
+				redisAuth = ":$(REDIS_PASSWORD)@"
 				env = append(env,
 					[]corev1.EnvVar{
 						{Name: "REDIS_PASSWORD",
@@ -372,6 +1004,24 @@ func getAirflowEnv(r *alpha1.AirflowCluster, saName string, base *alpha1.Airflow
 					}...)
 			}
 		}
+		// Set in the container env and not only by the image entrypoint so that
+		// exec probes and kubectl exec see the same broker and result backend.
+		env = append(env, []corev1.EnvVar{
+			{Name: "AIRFLOW__CELERY__BROKER_URL", Value: "redis://" + redisAuth + "$(REDIS_HOST):" + redisPort + "/1"},
+			{Name: "AIRFLOW__CELERY__RESULT_BACKEND", Value: "db+" + sqlConn(r, base, "$(SQL_PASSWORD)")},
+		}...)
+	}
+
+	if sp.Logging != nil {
+		env = append(env, remoteLoggingEnv(sp.Logging)...)
+	}
+
+	if sp.Metrics != nil {
+		env = append(env, []corev1.EnvVar{
+			{Name: "AIRFLOW__SCHEDULER__STATSD_ON", Value: "True"},
+			{Name: "AIRFLOW__SCHEDULER__STATSD_HOST", Value: common.RsrcName(r.Name, common.ValueAirflowComponentStatsd, "")},
+			{Name: "AIRFLOW__SCHEDULER__STATSD_PORT", Value: statsdPort},
+		}...)
 	}
 
 	// Do sorted key scan. To store the keys in slice in sorted order
@@ -391,8 +1041,70 @@ func getAirflowEnv(r *alpha1.AirflowCluster, saName string, base *alpha1.Airflow
 	return env
 }
 
+// remoteLogConn returns the airflow connection uri for the remote log storage.
+// The s3 credentials are picked up by boto from the environment.
+func remoteLogConn(s *alpha1.LoggingSpec) string {
+	if s.Storage.StorageProvider == alpha1.LogProviderGCS {
+		return "google-cloud-platform://?" + url.Values{
+			"extra__google_cloud_platform__key_path": {logSecretDir + "/key.json"},
+		}.Encode()
+	}
+	return "s3://?" + url.Values{
+		"host":        {s.Storage.Config["endpoint"]},
+		"region_name": {s.Storage.Config["region"]},
+	}.Encode()
+}
+
+func remoteLoggingEnv(s *alpha1.LoggingSpec) []corev1.EnvVar {
+	env := []corev1.EnvVar{
+		{Name: afc + "REMOTE_LOGGING", Value: "True"},
+		{Name: afc + "REMOTE_BASE_LOG_FOLDER", Value: s.RemoteBaseFolder()},
+		{Name: afc + "REMOTE_LOG_CONN_ID", Value: remoteLogConnID},
+		{Name: "AIRFLOW_CONN_" + strings.ToUpper(remoteLogConnID), Value: remoteLogConn(s)},
+	}
+	if s.Storage.StorageProvider != alpha1.LogProviderGCS {
+		env = append(env, []corev1.EnvVar{
+			{Name: "AWS_ACCESS_KEY_ID", ValueFrom: envFromSecret(s.Storage.SecretRef.Name, "AWS_ACCESS_KEY_ID")},
+			{Name: "AWS_SECRET_ACCESS_KEY", ValueFrom: envFromSecret(s.Storage.SecretRef.Name, "AWS_SECRET_ACCESS_KEY")},
+		}...)
+	}
+	return env
+}
+
+// addLogSecretVolume mounts the GCS service account key for remote logging
+func addLogSecretVolume(r *alpha1.AirflowCluster, spec *corev1.PodSpec) {
+	s := r.Spec.Logging
+	if s == nil || s.Storage.StorageProvider != alpha1.LogProviderGCS {
+		return
+	}
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name: logSecretVolName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: s.Storage.SecretRef.Name,
+				Items:      []corev1.KeyToPath{{Key: "key.json", Path: "key.json"}},
+			},
+		},
+	})
+	spec.Containers[0].VolumeMounts = append(spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      logSecretVolName,
+		MountPath: logSecretDir,
+		ReadOnly:  true,
+	})
+}
+
 // --------------- Global Cluster component -------------------------
 
+// Finalize forgets the readiness seen for the objects of the cluster and its
+// reconcile series
+func (c *Cluster) Finalize(rsrc interface{}, observed, dependent []reconciler.Object) error {
+	r := rsrc.(*alpha1.AirflowCluster)
+	c.events.Forget(r)
+	metrics.Forget(common.KindAirflowCluster, r)
+	finalizer.RemoveStandard(r)
+	return nil
+}
+
 // Observables asd
 func (c *Cluster) Observables(rsrc interface{}, labels map[string]string, dependent []reconciler.Object) []reconciler.Observable {
 	return k8s.NewObservables().
@@ -435,60 +1147,494 @@ func (c *Cluster) Objects(rsrc interface{}, rsrclabels map[string]string, observ
 
 // UpdateStatus use reconciled objects to update component status
 func (c *Cluster) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	handlerDone(c.events, rsrc, "Cluster", reconciled, err)
 	var period time.Duration
 	stts := &rsrc.(*alpha1.AirflowCluster).Status
 	ready := stts.ComponentMeta.UpdateStatus(reconciler.ObjectsByType(reconciled, k8s.Type))
 	stts.Meta.UpdateStatus(&ready, err)
+	r := rsrc.(*alpha1.AirflowCluster)
+	// the handlers before this one all reconciled the spec without errors
+	if err == nil {
+		stts.ObservedGeneration = r.Generation
+	}
+	stts.UIURL, stts.FlowerURL = "", ""
+	if r.Spec.UI != nil {
+		scheme := "http"
+		if r.Spec.UI.TLS != nil {
+			scheme = "https"
+		}
+		stts.UIURL = componentURL(r, common.ValueAirflowComponentUI, scheme, "8080", r.Spec.UI.Ingress)
+	}
+	if r.Spec.Flower != nil {
+		stts.FlowerURL = componentURL(r, common.ValueAirflowComponentFlower, "http", "5555", r.Spec.Flower.Ingress)
+	}
+	period = c.updateDagStatus(r)
+	if p := c.updateSchedulerStatus(r); p != 0 && (period == 0 || p < period) {
+		period = p
+	}
 	return period
 }
 
-// ------------------------------ Airflow UI -----------------------------------
+// componentURL returns the url of the Ingress of a component if it has
+// one and the in-cluster url of its Service otherwise
+func componentURL(r *alpha1.AirflowCluster, component, scheme, port string, ing *alpha1.IngressSpec) string {
+	if ing != nil {
+		return ing.URL()
+	}
+	return fmt.Sprintf("%s://%s.%s.svc:%s", scheme, common.RsrcName(r.Name, component, ""), r.Namespace, port)
+}
 
-// Observables asd
-func (s *UI) Observables(rsrc interface{}, labels map[string]string, dependent []reconciler.Object) []reconciler.Observable {
-	return k8s.NewObservables().
-		WithLabels(labels).
-		For(&appsv1.StatefulSetList{}).
-		For(&corev1.SecretList{}).
-		Get()
+// ingress routes the host of spec to the Service of the component. For a
+// backend serving https the nginx backend protocol is set unless the
+// annotations of spec set it.
+func ingress(spec *alpha1.IngressSpec, https bool) func(*reconciler.Object, interface{}) {
+	return func(o *reconciler.Object, v interface{}) {
+		ing := o.Obj.(*k8s.Object).Obj.(*extv1beta1.Ingress)
+		ing.Annotations = map[string]string{}
+		if https {
+			ing.Annotations[annotationBackendProtocol] = "HTTPS"
+		}
+		for k, v := range spec.Annotations {
+			ing.Annotations[k] = v
+		}
+		ing.Spec.Rules[0].Host = spec.Host
+		if spec.TLSSecret != "" {
+			ing.Spec.TLS = []extv1beta1.IngressTLS{{Hosts: []string{spec.Host}, SecretName: spec.TLSSecret}}
+		}
+	}
 }
 
-// DependentResources - return dependant resources
-func (s *UI) DependentResources(rsrc interface{}) []reconciler.Object {
-	return dependantResources(rsrc)
+// dagProbe prints the git-sync revision, the last change time of the synced
+// folder and the number of DAG files the scheduler would parse
+const dagProbe = `
+src="$1"
+rev=$(readlink "$src" || true)
+echo "rev=${rev##*rev-}"
+echo "synced=$(stat -c %Y "$src" 2>/dev/null || echo 0)"
+echo "dags=$(grep -rls --include='*.py' DAG "$AIRFLOW__CORE__DAGS_FOLDER" | xargs -r grep -ls airflow | wc -l)"
+`
+
+// dagSource returns the DAG source url, the folder it is synced into and
+// the pinned revision if the spec pins one
+func dagSource(s *alpha1.DagSpec) (string, string, string) {
+	switch {
+	case s.Git != nil:
+		return s.Git.Repo, airflowDagsBase + gitSyncDestDir, s.Git.Rev
+	case s.GCS != nil && s.GCS.Once:
+		revision := s.GCS.Prefix
+		if s.GCS.Object != "" {
+			revision = strconv.FormatInt(s.GCS.Generation, 10)
+		}
+		return s.GCS.Source(), airflowDagsBase + gCSSyncDestDir, revision
+	case s.GCS != nil:
+		return "gs://" + s.GCS.Bucket, airflowDagsBase + gCSSyncDestDir, ""
+	case s.Storage != nil:
+		return "s3://" + s.Storage.Config["bucket"] + "/" + s.Storage.Config["prefix"], airflowDagsBase + s3SyncDestDir, ""
+	}
+	return "", airflowDagsBase, ""
 }
 
-// Objects returns the list of resource/name for those resources created by
-func (s *UI) Objects(rsrc interface{}, rsrclabels map[string]string, observed, dependent, aggregated []reconciler.Object) ([]reconciler.Object, error) {
-	r := rsrc.(*alpha1.AirflowCluster)
-	if r.Spec.UI == nil {
-		return []reconciler.Object{}, nil
+// probeDags runs the dag probe in the airflow container of the pod
+func (c *Cluster) probeDags(pod *corev1.Pod, folder string) (alpha1.ComponentDagStatus, error) {
+	stts := alpha1.ComponentDagStatus{}
+	out, err := c.exec.Exec(pod, pod.Spec.Containers[0].Name, "/bin/sh", "-c", dagProbe, "dagprobe", folder)
+	if err != nil {
+		return stts, err
 	}
+	for _, line := range strings.Split(out, "\n") {
+		kv := strings.SplitN(strings.TrimSpace(line), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "rev":
+			stts.Revision = kv[1]
+		case "synced":
+			if ts, err := strconv.ParseInt(kv[1], 10, 64); err == nil && ts != 0 {
+				t := metav1.Unix(ts, 0)
+				stts.LastSyncTime = &t
+			}
+		case "dags":
+			if n, err := strconv.ParseInt(kv[1], 10, 32); err == nil {
+				stts.DagCount = int32(n)
+			}
+		}
+	}
+	return stts, nil
+}
 
-	if r.Spec.MemoryStore != nil && r.Spec.MemoryStore.Status.Host == "" {
-		return []reconciler.Object{}, nil
+// componentName returns the component of a pod from its statefulset name
+func componentName(r *alpha1.AirflowCluster, pod *corev1.Pod) string {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Kind == "StatefulSet" {
+			return strings.TrimPrefix(ref.Name, r.Name+"-")
+		}
 	}
+	return ""
+}
 
-	ngdata := templateValue(r, dependent, common.ValueAirflowComponentUI, rsrclabels, rsrclabels, map[string]string{"web": "8080"})
-	ngdata.Secret = map[string]string{
-		"password": base64.StdEncoding.EncodeToString(common.RandomAlphanumericString(16)),
+// hasDagVolume returns true for pods that mount the synced DAG folder
+func hasDagVolume(pod *corev1.Pod) bool {
+	for _, v := range pod.Spec.Volumes {
+		if v.Name == "dags-data" {
+			return true
+		}
 	}
+	return false
+}
 
-	return k8s.NewObjects().
+// updateDagStatus records the DAG revision synced by each component and
+// flags components that run different revisions
+func (c *Cluster) updateDagStatus(r *alpha1.AirflowCluster) time.Duration {
+	stts := &r.Status
+	if r.Spec.DAGs == nil {
+		stts.DAGs = nil
+		stts.Meta.RemoveCondition(conditionDagRevisionSkew)
+		return 0
+	}
+	if stts.DAGs != nil && stts.DAGs.LastCheckTime != nil {
+		if elapsed := time.Since(stts.DAGs.LastCheckTime.Time); elapsed < dagCheckInterval {
+			return dagCheckInterval - elapsed
+		}
+	}
+
+	source, folder, pinned := dagSource(r.Spec.DAGs)
+	now := metav1.Now()
+	dags := &alpha1.DagStatus{
+		Source:        source,
+		Components:    map[string]alpha1.ComponentDagStatus{},
+		LastCheckTime: &now,
+	}
+
+	pods := &corev1.PodList{}
+	labels := map[string]string{
+		gr.LabelResourceName:      r.Name,
+		gr.LabelResourceNamespace: r.Namespace,
+	}
+	err := c.client.List(context.TODO(), client.InNamespace(r.Namespace).MatchingLabels(labels), pods)
+	if err != nil {
+		log.Printf("%s/%s: listing pods for dag status: %v", r.Namespace, r.Name, err)
+		return dagCheckInterval
+	}
+
+	revisions := map[string][]string{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		component := componentName(r, pod)
+		if component == "" || pod.Status.Phase != corev1.PodRunning || !hasDagVolume(pod) {
+			continue
+		}
+		observed, err := c.probeDags(pod, folder)
+		if err != nil {
+			log.Printf("%s/%s: %v", r.Namespace, r.Name, err)
+			continue
+		}
+		if observed.Revision == "" {
+			observed.Revision = pinned
+		}
+		if observed.Revision != "" {
+			revisions[observed.Revision] = append(revisions[observed.Revision], pod.Name)
+		}
+		// the most recently synced pod represents the component
+		current, ok := dags.Components[component]
+		if !ok || current.LastSyncTime == nil ||
+			(observed.LastSyncTime != nil && observed.LastSyncTime.After(current.LastSyncTime.Time)) {
+			dags.Components[component] = observed
+		}
+	}
+
+	if len(revisions) > 1 {
+		var msg []string
+		for rev, podnames := range revisions {
+			sort.Strings(podnames)
+			msg = append(msg, rev+": "+strings.Join(podnames, ","))
+		}
+		sort.Strings(msg)
+		stts.Meta.SetCondition(conditionDagRevisionSkew, "RevisionsDiffer", strings.Join(msg, "; "))
+	} else {
+		for rev := range revisions {
+			dags.Revision = rev
+		}
+		stts.Meta.ClearCondition(conditionDagRevisionSkew, "RevisionsMatch", "all components run the same DAG revision")
+	}
+
+	stts.DAGs = dags
+	return dagCheckInterval
+}
+
+// updateSchedulerStatus records the DAG, run and heartbeat statistics of the
+// scheduler from the metadata DB, read by a Job. The last statistics stay
+// while the Job runs and when it failed.
+func (c *Cluster) updateSchedulerStatus(r *alpha1.AirflowCluster) time.Duration {
+	stts := &r.Status
+	if r.Spec.Scheduler == nil {
+		stts.Scheduler = nil
+		return 0
+	}
+	if stts.Scheduler == nil {
+		stts.Scheduler = &alpha1.SchedulerStatus{}
+	}
+	if last := stts.Scheduler.LastCheckTime; last != nil {
+		if elapsed := time.Since(last.Time); elapsed < schedulerCheckInterval {
+			return schedulerCheckInterval - elapsed
+		}
+	}
+	stats := schedulerStats{}
+	done, err := c.db.Run(&common.MetadataJob{
+		Name:      common.RsrcName(r.Name, "scheduler-stats", ""),
+		Owner:     r,
+		OwnerKind: common.KindAirflowCluster,
+		Namespace: r.Namespace,
+		Cluster:   r.Name,
+		Script:    schedulerStatsScript,
+		Input:     struct{}{},
+	}, &stats)
+	if err != nil {
+		// LastCheckTime stays at the last successful read so stale stats show
+		log.Printf("%s/%s: reading scheduler stats: %v", r.Namespace, r.Name, err)
+		return schedulerRetryInterval
+	}
+	if !done {
+		return schedulerPollInterval
+	}
+	now := metav1.Now()
+	sched := &alpha1.SchedulerStatus{
+		DagCount:        stats.DagCount,
+		PausedDagCount:  stats.PausedDagCount,
+		RunCount:        stats.RunCount,
+		RunningRunCount: stats.RunningRunCount,
+		QueuedRunCount:  stats.QueuedRunCount,
+		FailedTaskCount: stats.FailedTaskCount,
+		HeartbeatAge:    stats.HeartbeatAge,
+		LastCheckTime:   &now,
+	}
+	if stats.LastHeartbeat != "" {
+		if t, err := time.Parse(time.RFC3339Nano, stats.LastHeartbeat); err == nil {
+			sched.LastHeartbeatTime = &metav1.Time{Time: t}
+		}
+	}
+	stts.Scheduler = sched
+	return schedulerCheckInterval
+}
+
+// ------------------------------ Airflow UI -----------------------------------
+
+// Observables asd
+func (s *UI) Observables(rsrc interface{}, labels map[string]string, dependent []reconciler.Object) []reconciler.Observable {
+	return k8s.NewObservables().
+		WithLabels(labels).
+		For(&appsv1.StatefulSetList{}).
+		For(&corev1.SecretList{}).
+		For(&corev1.ServiceList{}).
+		For(&extv1beta1.IngressList{}).
+		For(&corev1.ConfigMapList{}).
+		Get()
+}
+
+// DependentResources - return dependant resources
+func (s *UI) DependentResources(rsrc interface{}) []reconciler.Object {
+	return dependantResources(rsrc)
+}
+
+// Objects returns the list of resource/name for those resources created by
+func (s *UI) Objects(rsrc interface{}, rsrclabels map[string]string, observed, dependent, aggregated []reconciler.Object) ([]reconciler.Object, error) {
+	r := rsrc.(*alpha1.AirflowCluster)
+	if r.Spec.UI == nil {
+		return []reconciler.Object{}, nil
+	}
+
+	if r.Spec.MemoryStore != nil && r.Spec.MemoryStore.Status.Host == "" {
+		return []reconciler.Object{}, nil
+	}
+
+	if dagsPending(r, observed) {
+		return []reconciler.Object{}, nil
+	}
+
+	ngdata := templateValue(r, dependent, common.ValueAirflowComponentUI, rsrclabels, rsrclabels, map[string]string{"web": "8080"})
+	err := setConfigHash(s.client, r, common.ValueAirflowComponentUI, observed, ngdata)
+	if err != nil {
+		return []reconciler.Object{}, err
+	}
+	ngdata.Secret = map[string]string{
+		"password": base64.StdEncoding.EncodeToString(common.RandomAlphanumericString(16)),
+	}
+
+	bag := k8s.NewObjects().
 		WithValue(ngdata).
 		WithTemplate("ui-sts.yaml", &appsv1.StatefulSetList{}, s.sts).
 		WithTemplate("secret.yaml", &corev1.SecretList{}, reconciler.NoUpdate).
-		Build()
+		WithTemplate("svc.yaml", &corev1.ServiceList{})
+	if r.Spec.UI.Ingress != nil {
+		bag.WithTemplate("ingress.yaml", &extv1beta1.IngressList{}, ingress(r.Spec.UI.Ingress, r.Spec.UI.TLS != nil))
+	}
+	if r.Spec.UI.Auth != nil {
+		bag.WithTemplate("webserver-configmap.yaml", &corev1.ConfigMapList{})
+	}
+	if tls := r.Spec.UI.TLS; tls != nil && tls.SelfSigned {
+		certs := *ngdata
+		certs.SecretName = uiTLSSecret(r)
+		certs.Secret, err = s.selfSignedCert(r, observed)
+		if err != nil {
+			return []reconciler.Object{}, err
+		}
+		bag.WithValue(&certs).WithTemplate("secret.yaml", &corev1.SecretList{})
+	}
+	return bag.Build()
+}
+
+// UpdateStatus sets the UIReady condition, the objects are tracked by Cluster
+func (s *UI) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	handlerDone(s.events, rsrc, "UI", reconciled, err)
+	r := rsrc.(*alpha1.AirflowCluster)
+	common.UpdateComponentCondition(&r.Status.Meta, "UI", r.Spec.UI != nil, reconciled, err)
+	if r.Spec.UI == nil {
+		return 0
+	}
+	return configRequeue(s.client, r, common.ValueAirflowComponentUI, "UI", err)
 }
 
 func (s *UI) sts(o *reconciler.Object, v interface{}) {
 	sts, r := updateSts(o, v)
 	sts.Spec.Template.Spec.Containers[0].Resources = r.Cluster.Spec.UI.Resources
+	addLogVolume(r, &sts.Spec.Template.Spec)
 	if IsPostgres(&r.Base.Spec) {
 		addPostgresUserDBContainer(r.Cluster, sts)
 	} else {
 		addMySQLUserDBContainer(r.Cluster, sts)
 	}
+	addUIAuth(r, sts)
+	addUITLS(r.Cluster, &sts.Spec.Template.Spec)
+}
+
+// uiTLSSecret returns the name of the secret with the certificate of the UI
+func uiTLSSecret(r *alpha1.AirflowCluster) string {
+	if r.Spec.UI.TLS.SecretName != "" {
+		return r.Spec.UI.TLS.SecretName
+	}
+	return common.RsrcName(r.Name, common.ValueAirflowComponentUI, "-tls")
+}
+
+// uiHosts returns the names the UI is reached by
+func uiHosts(r *alpha1.AirflowCluster) []string {
+	svc := common.RsrcName(r.Name, common.ValueAirflowComponentUI, "")
+	hosts := []string{
+		svc,
+		svc + "." + r.Namespace,
+		svc + "." + r.Namespace + ".svc",
+		svc + "." + r.Namespace + ".svc.cluster.local",
+		"localhost",
+	}
+	if r.Spec.UI.Ingress != nil {
+		hosts = append(hosts, r.Spec.UI.Ingress.Host)
+	}
+	return hosts
+}
+
+// selfSignedCert returns the data of the self signed certificate secret. The
+// observed certificate is kept until it is due for renewal or the hosts of
+// the UI change.
+func (s *UI) selfSignedCert(r *alpha1.AirflowCluster, observed []reconciler.Object) (map[string]string, error) {
+	name := uiTLSSecret(r)
+	hosts := uiHosts(r)
+	cert, key := []byte{}, []byte{}
+	for _, o := range observed {
+		if secret, ok := o.Obj.(*k8s.Object).Obj.(*corev1.Secret); ok && secret.Name == name {
+			cert, key = secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+		}
+	}
+	if !common.CertValid(cert, hosts, certRenewBefore) {
+		var err error
+		if cert, key, err = common.SelfSignedCert(hosts, certValidity); err != nil {
+			return nil, err
+		}
+	}
+	return map[string]string{
+		corev1.TLSCertKey:       base64.StdEncoding.EncodeToString(cert),
+		corev1.TLSPrivateKeyKey: base64.StdEncoding.EncodeToString(key),
+	}, nil
+}
+
+// addUITLS mounts the certificate of the UI and has the webserver serve https
+func addUITLS(r *alpha1.AirflowCluster, spec *corev1.PodSpec) {
+	if r.Spec.UI.TLS == nil {
+		return
+	}
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name: uiTLSVolName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: uiTLSSecret(r)},
+		},
+	})
+	ui := &spec.Containers[0]
+	ui.VolumeMounts = append(ui.VolumeMounts, corev1.VolumeMount{
+		Name:      uiTLSVolName,
+		MountPath: uiTLSDir,
+		ReadOnly:  true,
+	})
+	ui.Env = append(ui.Env,
+		corev1.EnvVar{Name: "AIRFLOW__WEBSERVER__WEB_SERVER_SSL_CERT", Value: uiTLSDir + "/" + corev1.TLSCertKey},
+		corev1.EnvVar{Name: "AIRFLOW__WEBSERVER__WEB_SERVER_SSL_KEY", Value: uiTLSDir + "/" + corev1.TLSPrivateKeyKey},
+	)
+}
+
+// addUIAuth mounts webserver_config.py into the UI and passes it the secrets
+// of the login method. Password logins get an init container creating the
+// Admin user.
+func addUIAuth(r *common.TemplateValue, sts *appsv1.StatefulSet) {
+	auth := r.Cluster.Spec.UI.Auth
+	if auth == nil {
+		return
+	}
+	spec := &sts.Spec.Template.Spec
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name: webserverConfigVolName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: common.RsrcName(r.Cluster.Name, common.ValueAirflowComponentUI, ""),
+				},
+				Items: []corev1.KeyToPath{{Key: webserverConfig, Path: webserverConfig}},
+			},
+		},
+	})
+	ui := &spec.Containers[0]
+	ui.VolumeMounts = append(ui.VolumeMounts, corev1.VolumeMount{
+		Name:      webserverConfigVolName,
+		MountPath: airflowHome + "/" + webserverConfig,
+		SubPath:   webserverConfig,
+		ReadOnly:  true,
+	})
+	if l := auth.LDAP; l != nil && l.BindSecretRef != nil {
+		ui.Env = append(ui.Env, corev1.EnvVar{
+			Name:      "LDAP_BIND_PASSWORD",
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: l.BindSecretRef},
+		})
+	}
+	if o := auth.OAuth; o != nil {
+		ui.Env = append(ui.Env,
+			corev1.EnvVar{Name: "OAUTH_CLIENT_ID", ValueFrom: envFromSecret(o.ClientSecretRef.Name, "client-id")},
+			corev1.EnvVar{Name: "OAUTH_CLIENT_SECRET", ValueFrom: envFromSecret(o.ClientSecretRef.Name, "client-secret")},
+		)
+	}
+	if p := auth.Password; p != nil {
+		admin := *ui.DeepCopy()
+		admin.Name = "create-admin"
+		admin.Command = []string{"/bin/sh", "-c", createAdmin(r.Cluster.Spec.UI.Version)}
+		admin.Args = nil
+		admin.Ports = nil
+		admin.LivenessProbe = nil
+		admin.ReadinessProbe = nil
+		email := envFromSecret(p.AdminSecretRef.Name, "email")
+		optional := true
+		email.SecretKeyRef.Optional = &optional
+		admin.Env = append(admin.Env,
+			corev1.EnvVar{Name: "ADMIN_USERNAME", ValueFrom: envFromSecret(p.AdminSecretRef.Name, "username")},
+			corev1.EnvVar{Name: "ADMIN_PASSWORD", ValueFrom: envFromSecret(p.AdminSecretRef.Name, "password")},
+			corev1.EnvVar{Name: "ADMIN_EMAIL", ValueFrom: email},
+		)
+		spec.InitContainers = append(spec.InitContainers, admin)
+	}
 }
 
 // ------------------------------ RedisSpec ---------------------------------------
@@ -541,17 +1687,55 @@ func (s *Redis) Objects(rsrc interface{}, rsrclabels map[string]string, observed
 		Build()
 }
 
+// UpdateStatus sets the RedisReady condition, the objects are tracked by Cluster
+func (s *Redis) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	handlerDone(s.events, rsrc, "Redis", reconciled, err)
+	r := rsrc.(*alpha1.AirflowCluster)
+	common.UpdateComponentCondition(&r.Status.Meta, "Redis", r.Spec.Redis != nil, reconciled, err)
+	return 0
+}
+
 // ------------------------------ Scheduler ---------------------------------------
 
+func gcsFetchContainer(s *alpha1.GCSSpec, volName string) corev1.Container {
+	env := []corev1.EnvVar{
+		{Name: "GCS_SOURCE", Value: s.Source()},
+		{Name: "GCS_FETCH_DEST", Value: "/home/airflow/gcs/" + gCSSyncDestDir},
+	}
+	script := `
+mkdir -p "$GCS_FETCH_DEST"
+gsutil -m rsync -r "$GCS_SOURCE" "$GCS_FETCH_DEST"
+`
+	if s.Object != "" {
+		script = `
+mkdir -p "$GCS_FETCH_DEST"
+gsutil cp "$GCS_SOURCE" /tmp/dags.tar.gz
+tar -xzf /tmp/dags.tar.gz -C "$GCS_FETCH_DEST"
+`
+	}
+	return corev1.Container{
+		Name:    "gcs-fetch",
+		Image:   alpha1.GCSfetchImage + ":" + alpha1.GCSfetchVersion,
+		Env:     env,
+		Command: []string{"/bin/sh"},
+		Args:    []string{"-ec", script},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      volName,
+				MountPath: "/home/airflow/gcs",
+			},
+		},
+	}
+}
+
 func gcsContainer(s *alpha1.GCSSpec, volName string) (bool, corev1.Container) {
-	init := false
+	if s.Once {
+		return true, gcsFetchContainer(s, volName)
+	}
 	container := corev1.Container{}
 	env := []corev1.EnvVar{
 		{Name: "GCS_BUCKET", Value: s.Bucket},
 	}
-	if s.Once {
-		init = true
-	}
 	container = corev1.Container{
 		Name:  "gcs-syncd",
 		Image: alpha1.GCSsyncImage + ":" + alpha1.GCSsyncVersion,
@@ -565,7 +1749,7 @@ func gcsContainer(s *alpha1.GCSSpec, volName string) (bool, corev1.Container) {
 		},
 	}
 
-	return init, container
+	return false, container
 }
 
 func gitContainer(s *alpha1.GitSpec, volName string) (bool, corev1.Container) {
@@ -585,6 +1769,14 @@ func gitContainer(s *alpha1.GitSpec, volName string) (bool, corev1.Container) {
 			{Name: "GIT_SYNC_USERNAME", Value: s.User},
 		}...)
 	}
+	if s.SSHSecretRef != nil {
+		env = append(env, []corev1.EnvVar{
+			{Name: "GIT_SYNC_SSH", Value: "true"},
+			{Name: "GIT_SSH_KEY_FILE", Value: gitSecretDir + "/ssh"},
+			{Name: "GIT_KNOWN_HOSTS", Value: "true"},
+			{Name: "GIT_SSH_KNOWN_HOSTS_FILE", Value: gitSecretDir + "/known_hosts"},
+		}...)
+	}
 	if s.Once {
 		init = true
 	}
@@ -606,10 +1798,71 @@ func gitContainer(s *alpha1.GitSpec, volName string) (bool, corev1.Container) {
 			},
 		},
 	}
+	if s.SSHSecretRef != nil {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      gitSecretVolName,
+			MountPath: gitSecretDir,
+			ReadOnly:  true,
+		})
+	}
 
 	return init, container
 }
 
+func gitSecretVolume(s *alpha1.GitSpec) corev1.Volume {
+	// git-sync runs as a non root user and needs group read access to the key
+	mode := int32(0440)
+	return corev1.Volume{
+		Name: gitSecretVolName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  s.SSHSecretRef.Name,
+				DefaultMode: &mode,
+				Items: []corev1.KeyToPath{
+					{Key: "gitSshKey", Path: "ssh"},
+					{Key: "known_hosts", Path: "known_hosts"},
+				},
+			},
+		},
+	}
+}
+
+func s3Container(s *alpha1.StorageSpec, volName string) (bool, corev1.Container) {
+	interval := s.Config["interval"]
+	if interval == "" {
+		interval = s3SyncInterval
+	}
+	env := []corev1.EnvVar{
+		{Name: "AWS_ACCESS_KEY_ID", ValueFrom: envFromSecret(s.SecretRef.Name, "AWS_ACCESS_KEY_ID")},
+		{Name: "AWS_SECRET_ACCESS_KEY", ValueFrom: envFromSecret(s.SecretRef.Name, "AWS_SECRET_ACCESS_KEY")},
+		{Name: "AWS_DEFAULT_REGION", Value: s.Config["region"]},
+		{Name: "S3_ENDPOINT", Value: s.Config["endpoint"]},
+		{Name: "S3_SOURCE", Value: "s3://" + s.Config["bucket"] + "/" + strings.TrimPrefix(s.Config["prefix"], "/")},
+		{Name: "S3_SYNC_DEST", Value: "/tmp/s3/" + s3SyncDestDir},
+		{Name: "S3_SYNC_INTERVAL", Value: interval},
+	}
+	container := corev1.Container{
+		Name:    "s3-sync",
+		Image:   s.Image + ":" + s.Version,
+		Env:     env,
+		Command: []string{"/bin/sh"},
+		Args: []string{"-c", `
+while true; do
+  aws s3 sync --delete --endpoint-url "$S3_ENDPOINT" "$S3_SOURCE" "$S3_SYNC_DEST"
+  sleep "$S3_SYNC_INTERVAL"
+done
+`},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      volName,
+				MountPath: "/tmp/s3",
+			},
+		},
+	}
+
+	return false, container
+}
+
 func dagContainer(s *alpha1.DagSpec, volName string) (bool, corev1.Container) {
 	init := false
 	container := corev1.Container{}
@@ -620,17 +1873,88 @@ func dagContainer(s *alpha1.DagSpec, volName string) (bool, corev1.Container) {
 	if s.GCS != nil {
 		return gcsContainer(s.GCS, volName)
 	}
+	if s.Storage != nil {
+		return s3Container(s.Storage, volName)
+	}
 
 	return init, container
 }
 
+// gitSSH returns the git spec if DAGs are synced from git over ssh
+func gitSSH(r *alpha1.AirflowCluster) *alpha1.GitSpec {
+	if r.Spec.DAGs == nil || r.Spec.DAGs.Git == nil || r.Spec.DAGs.Git.SSHSecretRef == nil {
+		return nil
+	}
+	return r.Spec.DAGs.Git
+}
+
+// heartbeatScript exits with an error when the scheduler job of the pod did
+// not heartbeat in the number of seconds passed as argument. It is used on
+// airflow 1.x which has no jobs check command.
+const heartbeatScript = `
+import sys
+from airflow import settings
+from airflow.jobs import BaseJob
+from airflow.utils import timezone
+from airflow.utils.net import get_hostname
+
+session = settings.Session()
+job = session.query(BaseJob).filter(BaseJob.job_type == 'SchedulerJob', BaseJob.hostname == get_hostname()).order_by(BaseJob.latest_heartbeat.desc()).first()
+if not job or not job.latest_heartbeat:
+    sys.exit('no scheduler job for ' + get_hostname())
+age = (timezone.utcnow() - job.latest_heartbeat).total_seconds()
+if age > int(sys.argv[1]):
+    sys.exit('last scheduler heartbeat %ds ago' % age)
+`
+
+// heartbeatProbe checks the heartbeat of the scheduler job in the pod with
+// threshold seconds. All fields are set so that the sts does not differ from
+// the defaulted one.
+func heartbeatProbe(version string, threshold, delay, period, failures int32) *corev1.Probe {
+	t := strconv.Itoa(int(threshold))
+	command := []string{"python", "-c", heartbeatScript, t}
+	if !strings.HasPrefix(version, "1.") {
+		command = []string{"sh", "-c", "AIRFLOW__SCHEDULER__SCHEDULER_HEALTH_CHECK_THRESHOLD=" + t +
+			" exec airflow jobs check --job-type SchedulerJob --local"}
+	}
+	return &corev1.Probe{
+		Handler:             corev1.Handler{Exec: &corev1.ExecAction{Command: command}},
+		InitialDelaySeconds: delay,
+		PeriodSeconds:       period,
+		TimeoutSeconds:      30,
+		SuccessThreshold:    1,
+		FailureThreshold:    failures,
+	}
+}
+
 func (s *Scheduler) sts(o *reconciler.Object, v interface{}) {
 	sts, r := updateSts(o, v)
 	if r.Cluster.Spec.Executor == alpha1.ExecutorK8s {
 		sts.Spec.Template.Spec.ServiceAccountName = sts.Name
 	}
-	sts.Spec.Template.Spec.Containers[0].Resources = r.Cluster.Spec.Scheduler.Resources
-	sts.Spec.Template.Spec.Containers[1].Env = getAirflowPrometheusEnv(r.Cluster, r.Base)
+	spec := r.Cluster.Spec.Scheduler
+	container := &sts.Spec.Template.Spec.Containers[0]
+	container.Resources = spec.Resources
+	container.LivenessProbe = heartbeatProbe(spec.Version, spec.LivenessThreshold, 120, 60, 3)
+	container.ReadinessProbe = heartbeatProbe(spec.Version, spec.ReadinessThreshold, 30, 30, 1)
+	addLogVolume(r, &sts.Spec.Template.Spec)
+	if r.Cluster.Spec.Metrics != nil {
+		// the statsd-exporter of the cluster replaces the exporter sidecar
+		containers := []corev1.Container{}
+		for _, c := range sts.Spec.Template.Spec.Containers {
+			if c.Name != "metrics" {
+				containers = append(containers, c)
+			}
+		}
+		sts.Spec.Template.Spec.Containers = containers
+	}
+}
+
+// configmap merges the config sections of the spec into the airflow.cfg
+func (s *Scheduler) configmap(o *reconciler.Object, v interface{}) {
+	r := v.(*common.TemplateValue)
+	cm := o.Obj.(*k8s.Object).Obj.(*corev1.ConfigMap)
+	cm.Data[airflowCfg] = common.MergeConfig(cm.Data[airflowCfg], r.Cluster.Spec.Config.Sections)
 }
 
 // DependentResources - return dependant resources
@@ -640,6 +1964,9 @@ func (s *Scheduler) DependentResources(rsrc interface{}) []reconciler.Object {
 	if r.Spec.Executor == alpha1.ExecutorK8s {
 		sqlSecret := common.RsrcName(r.Name, common.ValueAirflowComponentUI, "")
 		resources = append(resources, k8s.ReferredItem(&corev1.Secret{}, sqlSecret, r.Namespace))
+		if git := gitSSH(r); git != nil {
+			resources = append(resources, k8s.ReferredItem(&corev1.Secret{}, git.SSHSecretRef.Name, r.Namespace))
+		}
 	}
 	return resources
 }
@@ -668,6 +1995,10 @@ func (s *Scheduler) Objects(rsrc interface{}, rsrclabels map[string]string, obse
 		return []reconciler.Object{}, nil
 	}
 
+	if dagsPending(r, observed) {
+		return []reconciler.Object{}, nil
+	}
+
 	b := k8s.GetItem(dependent, &alpha1.AirflowBase{}, r.Spec.AirflowBaseRef.Name, r.Namespace)
 	base := b.(*alpha1.AirflowBase)
 	bag := k8s.NewObjects()
@@ -676,40 +2007,81 @@ func (s *Scheduler) Objects(rsrc interface{}, rsrclabels map[string]string, obse
 		if git != nil && git.CredSecretRef != nil {
 			bag.WithReferredItem(&corev1.Secret{}, git.CredSecretRef.Name, r.Namespace)
 		}
+		if git != nil && git.SSHSecretRef != nil {
+			bag.WithReferredItem(&corev1.Secret{}, git.SSHSecretRef.Name, r.Namespace)
+		}
+		storage := r.Spec.DAGs.Storage
+		if storage != nil && storage.SecretRef != nil {
+			bag.WithReferredItem(&corev1.Secret{}, storage.SecretRef.Name, r.Namespace)
+		}
+	}
+	if l := r.Spec.Logging; l != nil {
+		bag.WithReferredItem(&corev1.Secret{}, l.Storage.SecretRef.Name, r.Namespace)
+	}
+	if p := r.Spec.Plugins; p != nil {
+		if p.Git != nil && p.Git.CredSecretRef != nil {
+			bag.WithReferredItem(&corev1.Secret{}, p.Git.CredSecretRef.Name, r.Namespace)
+		}
+		if p.Git != nil && p.Git.SSHSecretRef != nil {
+			bag.WithReferredItem(&corev1.Secret{}, p.Git.SSHSecretRef.Name, r.Namespace)
+		}
+		if p.Storage != nil && p.Storage.SecretRef != nil {
+			bag.WithReferredItem(&corev1.Secret{}, p.Storage.SecretRef.Name, r.Namespace)
+		}
 	}
 
 	ngdata := templateValue(r, dependent, common.ValueAirflowComponentScheduler, rsrclabels, rsrclabels, nil)
 	bag.WithValue(ngdata).WithFolder("templates/")
+	if err := setConfigHash(s.client, r, common.ValueAirflowComponentScheduler, observed, ngdata); err != nil {
+		return []reconciler.Object{}, err
+	}
 
 	if r.Spec.Executor == alpha1.ExecutorK8s {
-		sqlSvcName := common.RsrcName(r.Spec.AirflowBaseRef.Name, common.ValueAirflowComponentSQL, "")
 		sqlSecret := common.RsrcName(r.Name, common.ValueAirflowComponentUI, "")
 		se := k8s.GetItem(dependent, &corev1.Secret{}, sqlSecret, r.Namespace)
 		secret := se.(*corev1.Secret)
 
-		dbPrefix := "mysql"
-		port := "3306"
-		if base.Spec.Postgres != nil {
-			dbPrefix = "postgresql+psycopg2"
-			port = "5432"
+		ngdata.SQLConn = sqlConn(r, base, string(secret.Data["password"]))
+		if r.Spec.Logging != nil {
+			ngdata.LogConn = remoteLogConn(r.Spec.Logging)
 		}
-		conn := dbPrefix + "://" + r.Spec.Scheduler.DBUser + ":" + string(secret.Data["password"]) + "@" + sqlSvcName + ":" + port + "/" + r.Spec.Scheduler.DBName
 
-		ngdata.SQLConn = conn
-		bag.WithTemplate("airflow-configmap.yaml", &corev1.ConfigMapList{})
+		// task pods read known_hosts from a configmap, copy it from the ssh secret
+		if git := gitSSH(r); git != nil {
+			se := k8s.GetItem(dependent, &corev1.Secret{}, git.SSHSecretRef.Name, r.Namespace)
+			knownHosts := string(se.(*corev1.Secret).Data["known_hosts"])
+			bag.WithTemplate("known-hosts-configmap.yaml", &corev1.ConfigMapList{},
+				func(o *reconciler.Object, v interface{}) {
+					cm := o.Obj.(*k8s.Object).Obj.(*corev1.ConfigMap)
+					cm.Data = map[string]string{"known_hosts": knownHosts}
+				})
+		}
 	}
 
-	return bag.WithTemplate("scheduler-sts.yaml", &appsv1.StatefulSetList{}, s.sts).
+	return bag.WithTemplate("airflow-configmap.yaml", &corev1.ConfigMapList{}, s.configmap).
+		WithTemplate("scheduler-sts.yaml", &appsv1.StatefulSetList{}, s.sts).
 		WithTemplate("serviceaccount.yaml", &corev1.ServiceAccountList{}, reconciler.NoUpdate).
 		WithTemplate("rolebinding.yaml", &rbacv1.RoleBindingList{}).
 		Build()
 }
 
+// UpdateStatus sets the SchedulerReady condition, the objects are tracked by Cluster
+func (s *Scheduler) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	handlerDone(s.events, rsrc, "Scheduler", reconciled, err)
+	r := rsrc.(*alpha1.AirflowCluster)
+	common.UpdateComponentCondition(&r.Status.Meta, "Scheduler", r.Spec.Scheduler != nil, reconciled, err)
+	if r.Spec.Scheduler == nil {
+		return 0
+	}
+	return configRequeue(s.client, r, common.ValueAirflowComponentScheduler, "Scheduler", err)
+}
+
 // ------------------------------ Worker ----------------------------------------
 
 func (s *Worker) sts(o *reconciler.Object, v interface{}) {
 	sts, r := updateSts(o, v)
 	sts.Spec.Template.Spec.Containers[0].Resources = r.Cluster.Spec.Worker.Resources
+	addLogVolume(r, &sts.Spec.Template.Spec)
 }
 
 // Observables asd
@@ -739,7 +2111,14 @@ func (s *Worker) Objects(rsrc interface{}, rsrclabels map[string]string, observe
 		return []reconciler.Object{}, nil
 	}
 
+	if dagsPending(r, observed) {
+		return []reconciler.Object{}, nil
+	}
+
 	ngdata := templateValue(r, dependent, common.ValueAirflowComponentWorker, rsrclabels, rsrclabels, map[string]string{"wlog": "8793"})
+	if err := setConfigHash(s.client, r, common.ValueAirflowComponentWorker, observed, ngdata); err != nil {
+		return []reconciler.Object{}, err
+	}
 
 	return k8s.NewObjects().
 		WithValue(ngdata).
@@ -748,6 +2127,17 @@ func (s *Worker) Objects(rsrc interface{}, rsrclabels map[string]string, observe
 		Build()
 }
 
+// UpdateStatus sets the WorkerReady condition, the objects are tracked by Cluster
+func (s *Worker) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	handlerDone(s.events, rsrc, "Worker", reconciled, err)
+	r := rsrc.(*alpha1.AirflowCluster)
+	common.UpdateComponentCondition(&r.Status.Meta, "Worker", r.Spec.Worker != nil, reconciled, err)
+	if r.Spec.Worker == nil {
+		return 0
+	}
+	return configRequeue(s.client, r, common.ValueAirflowComponentWorker, "Worker", err)
+}
+
 // ------------------------------ Flower ---------------------------------------
 
 // Observables asd
@@ -755,6 +2145,8 @@ func (s *Flower) Observables(rsrc interface{}, labels map[string]string, depende
 	return k8s.NewObservables().
 		WithLabels(labels).
 		For(&appsv1.StatefulSetList{}).
+		For(&corev1.ServiceList{}).
+		For(&extv1beta1.IngressList{}).
 		Get()
 }
 
@@ -772,12 +2164,34 @@ func (s *Flower) Objects(rsrc interface{}, rsrclabels map[string]string, observe
 	if r.Spec.MemoryStore != nil && r.Spec.MemoryStore.Status.Host == "" {
 		return []reconciler.Object{}, nil
 	}
+
+	if dagsPending(r, observed) {
+		return []reconciler.Object{}, nil
+	}
 	ngdata := templateValue(r, dependent, common.ValueAirflowComponentFlower, rsrclabels, rsrclabels, map[string]string{"flower": "5555"})
+	if err := setConfigHash(s.client, r, common.ValueAirflowComponentFlower, observed, ngdata); err != nil {
+		return []reconciler.Object{}, err
+	}
 
-	return k8s.NewObjects().
+	bag := k8s.NewObjects().
 		WithValue(ngdata).
 		WithTemplate("flower-sts.yaml", &appsv1.StatefulSetList{}, s.sts).
-		Build()
+		WithTemplate("svc.yaml", &corev1.ServiceList{})
+	if r.Spec.Flower.Ingress != nil {
+		bag.WithTemplate("ingress.yaml", &extv1beta1.IngressList{}, ingress(r.Spec.Flower.Ingress, false))
+	}
+	return bag.Build()
+}
+
+// UpdateStatus sets the FlowerReady condition, the objects are tracked by Cluster
+func (s *Flower) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	handlerDone(s.events, rsrc, "Flower", reconciled, err)
+	r := rsrc.(*alpha1.AirflowCluster)
+	common.UpdateComponentCondition(&r.Status.Meta, "Flower", r.Spec.Flower != nil, reconciled, err)
+	if r.Spec.Flower == nil {
+		return 0
+	}
+	return configRequeue(s.client, r, common.ValueAirflowComponentFlower, "Flower", err)
 }
 
 func (s *Flower) sts(o *reconciler.Object, v interface{}) {
@@ -785,6 +2199,815 @@ func (s *Flower) sts(o *reconciler.Object, v interface{}) {
 	sts.Spec.Template.Spec.Containers[0].Resources = r.Cluster.Spec.Flower.Resources
 }
 
+// ------------------------------ Logs ---------------------------------------
+
+// Observables for the log volume claim and the retention job
+func (s *Logs) Observables(rsrc interface{}, labels map[string]string, dependent []reconciler.Object) []reconciler.Observable {
+	return k8s.NewObservables().
+		WithLabels(labels).
+		For(&corev1.PersistentVolumeClaimList{}).
+		For(&batchv1beta1.CronJobList{}).
+		Get()
+}
+
+// DependentResources - return dependant resources
+func (s *Logs) DependentResources(rsrc interface{}) []reconciler.Object {
+	return dependantResources(rsrc)
+}
+
+// Objects returns the log volume claim and the retention CronJob
+func (s *Logs) Objects(rsrc interface{}, rsrclabels map[string]string, observed, dependent, aggregated []reconciler.Object) ([]reconciler.Object, error) {
+	r := rsrc.(*alpha1.AirflowCluster)
+	if r.Spec.LogVolume == nil {
+		return []reconciler.Object{}, nil
+	}
+	ngdata := templateValue(r, dependent, common.ValueAirflowComponentLogs, rsrclabels, rsrclabels, nil)
+	bag := k8s.NewObjects().WithValue(ngdata).WithFolder("templates/")
+	if r.Spec.LogVolume.Claim != nil {
+		// the claim spec cannot be changed once bound
+		bag.WithTemplate("logs-pvc.yaml", &corev1.PersistentVolumeClaimList{}, reconciler.NoUpdate, s.pvc)
+	}
+	return bag.WithTemplate("logs-retention-cronjob.yaml", &batchv1beta1.CronJobList{}, s.cronjob).
+		Build()
+}
+
+// UpdateStatus records the reconcile of the handler, the objects are tracked by Cluster
+func (s *Logs) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	handlerDone(s.events, rsrc, "Logs", reconciled, err)
+	return 0
+}
+
+func (s *Logs) pvc(o *reconciler.Object, v interface{}) {
+	r := v.(*common.TemplateValue)
+	pvc := o.Obj.(*k8s.Object).Obj.(*corev1.PersistentVolumeClaim)
+	pvc.Spec = *r.Cluster.Spec.LogVolume.Claim.Spec.DeepCopy()
+}
+
+func (s *Logs) cronjob(o *reconciler.Object, v interface{}) {
+	r := v.(*common.TemplateValue)
+	cj := o.Obj.(*k8s.Object).Obj.(*batchv1beta1.CronJob)
+	spec := &cj.Spec.JobTemplate.Spec.Template.Spec
+	vol, mount := logVolume(r)
+	spec.Volumes = append(spec.Volumes, vol)
+	spec.Containers[0].VolumeMounts = append(spec.Containers[0].VolumeMounts, mount)
+}
+
+// ------------------------------ Metrics ---------------------------------------
+
+// defaultStatsdMappings turns the names of the dags, tasks, operators and pools
+// in the StatsD metric names of airflow 1.10 into labels
+const defaultStatsdMappings = `mappings:
+- match: airflow.dagrun.duration.*.*
+  name: airflow_dagrun_duration
+  labels:
+    state: "$1"
+    dag_id: "$2"
+- match: airflow.dagrun.dependency-check.*
+  name: airflow_dagrun_dependency_check
+  labels:
+    dag_id: "$1"
+- match: airflow.dagrun.schedule_delay.*
+  name: airflow_dagrun_schedule_delay
+  labels:
+    dag_id: "$1"
+- match: airflow.dag.*.*.duration
+  name: airflow_task_duration
+  labels:
+    dag_id: "$1"
+    task_id: "$2"
+- match: airflow.dag_processing.last_runtime.*
+  name: airflow_dag_processing_last_runtime
+  labels:
+    dag_file: "$1"
+- match: airflow.dag_processing.last_run.seconds_ago.*
+  name: airflow_dag_processing_last_run_seconds_ago
+  labels:
+    dag_file: "$1"
+- match: airflow.operator_failures_*
+  name: airflow_operator_failures
+  labels:
+    operator: "$1"
+- match: airflow.operator_successes_*
+  name: airflow_operator_successes
+  labels:
+    operator: "$1"
+- match: airflow.pool.*.*
+  name: airflow_pool_$1
+  labels:
+    pool: "$2"
+- match: airflow.executor.*
+  name: airflow_executor_$1
+- match: airflow.*_start
+  name: airflow_job_start
+  labels:
+    job: "$1"
+- match: airflow.*_end
+  name: airflow_job_end
+  labels:
+    job: "$1"
+`
+
+// statsdMappingsOf returns the mapping config of the statsd-exporter of the cluster
+func statsdMappingsOf(r *alpha1.AirflowCluster) string {
+	if r.Spec.Metrics.Mappings != "" {
+		return r.Spec.Metrics.Mappings
+	}
+	return defaultStatsdMappings
+}
+
+func (s *Metrics) sts(o *reconciler.Object, v interface{}) {
+	r := v.(*common.TemplateValue)
+	sts := o.Obj.(*k8s.Object).Obj.(*appsv1.StatefulSet)
+	sts.Spec.Template.Spec.Containers[0].Resources = r.Cluster.Spec.Metrics.Resources
+	// the exporter reads the mappings on start only
+	if sts.Spec.Template.Annotations == nil {
+		sts.Spec.Template.Annotations = map[string]string{}
+	}
+	sts.Spec.Template.Annotations[annotationConfigHash] = r.ConfigHash
+}
+
+func (s *Metrics) configmap(o *reconciler.Object, v interface{}) {
+	r := v.(*common.TemplateValue)
+	cm := o.Obj.(*k8s.Object).Obj.(*corev1.ConfigMap)
+	cm.Data = map[string]string{statsdMappings: statsdMappingsOf(r.Cluster)}
+}
+
+// svc receives the StatsD packets over UDP
+func (s *Metrics) svc(o *reconciler.Object, v interface{}) {
+	svc := o.Obj.(*k8s.Object).Obj.(*corev1.Service)
+	for i := range svc.Spec.Ports {
+		if svc.Spec.Ports[i].Name == "statsd" {
+			svc.Spec.Ports[i].Protocol = corev1.ProtocolUDP
+		}
+	}
+}
+
+// serviceMonitor builds the prometheus-operator ServiceMonitor of the
+// exporter. Its CRD may not be installed so it is kept unstructured.
+func (s *Metrics) serviceMonitor(v interface{}) metav1.Object {
+	r := v.(*common.TemplateValue)
+	sm := r.Cluster.Spec.Metrics.ServiceMonitor
+	labels := map[string]string{}
+	for k, v := range r.Labels {
+		labels[k] = v
+	}
+	for k, v := range sm.Labels {
+		labels[k] = v
+	}
+	selector := map[string]interface{}{}
+	for k, v := range r.Selector {
+		selector[k] = v
+	}
+	endpoint := map[string]interface{}{"port": "metrics"}
+	if sm.Interval != "" {
+		endpoint["interval"] = sm.Interval
+	}
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(serviceMonitorGVK)
+	u.SetName(r.SvcName)
+	u.SetNamespace(r.Namespace)
+	u.SetLabels(labels)
+	u.Object["spec"] = map[string]interface{}{
+		"selector":  map[string]interface{}{"matchLabels": selector},
+		"endpoints": []interface{}{endpoint},
+	}
+	return u
+}
+
+// serviceMonitorList returns the list to observe ServiceMonitors with
+func serviceMonitorList() *unstructured.UnstructuredList {
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(serviceMonitorGVK.GroupVersion().WithKind(serviceMonitorGVK.Kind + "List"))
+	return l
+}
+
+// Observables - the ServiceMonitor is only observed when asked for, its CRD may be missing
+func (s *Metrics) Observables(rsrc interface{}, labels map[string]string, dependent []reconciler.Object) []reconciler.Observable {
+	r := rsrc.(*alpha1.AirflowCluster)
+	obs := k8s.NewObservables().
+		WithLabels(labels).
+		For(&appsv1.StatefulSetList{}).
+		For(&corev1.ConfigMapList{}).
+		For(&corev1.ServiceList{})
+	if r.Spec.Metrics != nil && r.Spec.Metrics.ServiceMonitor != nil {
+		obs.For(serviceMonitorList())
+	}
+	return obs.Get()
+}
+
+// Objects returns the statsd-exporter and the Service the airflow components send StatsD packets to
+func (s *Metrics) Objects(rsrc interface{}, rsrclabels map[string]string, observed, dependent, aggregated []reconciler.Object) ([]reconciler.Object, error) {
+	r := rsrc.(*alpha1.AirflowCluster)
+	if r.Spec.Metrics == nil {
+		return []reconciler.Object{}, nil
+	}
+	ports := map[string]string{"statsd": statsdPort, "metrics": statsdMetricsPort}
+	ngdata := templateValue(r, dependent, common.ValueAirflowComponentStatsd, rsrclabels, rsrclabels, ports)
+	sum := sha256.Sum256([]byte(statsdMappingsOf(r)))
+	ngdata.ConfigHash = hex.EncodeToString(sum[:])
+
+	bag := k8s.NewObjects().
+		WithValue(ngdata).
+		WithTemplate("statsd-configmap.yaml", &corev1.ConfigMapList{}, s.configmap).
+		WithTemplate("statsd-sts.yaml", &appsv1.StatefulSetList{}, s.sts).
+		WithTemplate("svc.yaml", &corev1.ServiceList{}, s.svc)
+	if r.Spec.Metrics.ServiceMonitor != nil {
+		bag.WithObject(s.serviceMonitor, serviceMonitorList())
+	}
+	return bag.Build()
+}
+
+// Differs compares the spec and labels of the ServiceMonitor, the resource
+// manager cannot look into unstructured objects
+func (s *Metrics) Differs(expected reconciler.Object, observed reconciler.Object) bool {
+	e, ok := expected.Obj.(*k8s.Object).Obj.(*unstructured.Unstructured)
+	if !ok {
+		return true
+	}
+	o := observed.Obj.(*k8s.Object).Obj.(*unstructured.Unstructured)
+	return !reflect.DeepEqual(e.Object["spec"], o.Object["spec"]) || !reflect.DeepEqual(e.GetLabels(), o.GetLabels())
+}
+
+// UpdateStatus records the reconcile of the handler, the objects are tracked by Cluster
+func (s *Metrics) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	handlerDone(s.events, rsrc, "Metrics", reconciled, err)
+	return 0
+}
+
+// ------------------------------ Keys ---------------------------------------
+
+// newFernetKey returns a random url safe base64 encoded 32 byte key
+func newFernetKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(key), nil
+}
+
+// rolledOut returns true when every statefulset of the cluster runs the
+// latest revision of its pod template
+func rolledOut(c client.Client, r *alpha1.AirflowCluster) (bool, error) {
+	list := &appsv1.StatefulSetList{}
+	labels := map[string]string{
+		gr.LabelResourceName:      r.Name,
+		gr.LabelResourceNamespace: r.Namespace,
+	}
+	if err := c.List(context.TODO(), client.InNamespace(r.Namespace).MatchingLabels(labels), list); err != nil {
+		return false, err
+	}
+	for _, sts := range list.Items {
+		st := sts.Status
+		if st.ObservedGeneration < sts.Generation || st.CurrentRevision != st.UpdateRevision ||
+			(sts.Spec.Replicas != nil && st.UpdatedReplicas < *sts.Spec.Replicas) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Observables for the rotation job
+func (s *Keys) Observables(rsrc interface{}, labels map[string]string, dependent []reconciler.Object) []reconciler.Observable {
+	return k8s.NewObservables().
+		WithLabels(labels).
+		For(&corev1.SecretList{}).
+		For(&batchv1.JobList{}).
+		Get()
+}
+
+// DependentResources - return dependant resources
+func (s *Keys) DependentResources(rsrc interface{}) []reconciler.Object {
+	return dependantResources(rsrc)
+}
+
+// Objects returns the keys secret and while a rotation is in progress the
+// job that re-encrypts the metadata DB. A rotation puts the new key in front
+// of the old one, waits for the pods to pick up both, re-encrypts and then
+// drops the old key.
+func (s *Keys) Objects(rsrc interface{}, rsrclabels map[string]string, observed, dependent, aggregated []reconciler.Object) ([]reconciler.Object, error) {
+	r := rsrc.(*alpha1.AirflowCluster)
+	fernetKey, err := newFernetKey()
+	if err != nil {
+		return []reconciler.Object{}, err
+	}
+	ngdata := templateValue(r, dependent, common.ValueAirflowComponentKeys, rsrclabels, rsrclabels, nil)
+	ngdata.Secret = map[string]string{
+		fernetKeyKey:          base64.StdEncoding.EncodeToString([]byte(fernetKey)),
+		webserverSecretKeyKey: base64.StdEncoding.EncodeToString(common.RandomAlphanumericString(32)),
+	}
+	bag := k8s.NewObjects().WithValue(ngdata)
+
+	var secret *corev1.Secret
+	var job *batchv1.Job
+	for _, o := range observed {
+		switch obj := o.Obj.(*k8s.Object).Obj.(type) {
+		case *corev1.Secret:
+			secret = obj
+		case *batchv1.Job:
+			job = obj
+		}
+	}
+	keys, runJob, err := s.rotate(r, secret, job, fernetKey)
+	if err != nil {
+		return []reconciler.Object{}, err
+	}
+	if keys == "" {
+		bag.WithTemplate("secret.yaml", &corev1.SecretList{}, reconciler.NoUpdate)
+	} else {
+		bag.WithTemplate("secret.yaml", &corev1.SecretList{}, withFernetKeys(secret, keys, r.Status.FernetKey))
+	}
+	if runJob {
+		return s.job(r, dependent, rsrclabels, bag)
+	}
+	return bag.Build()
+}
+
+// rotate steps the rotation of the fernet key. It returns the fernet keys the
+// secret has to hold, empty to leave it alone, and whether the rotation job
+// has to run. The secret is annotated with the rotation it was written for so
+// that a failed write is retried.
+func (s *Keys) rotate(r *alpha1.AirflowCluster, secret *corev1.Secret, job *batchv1.Job, fernetKey string) (string, bool, error) {
+	if r.Status.FernetKey == nil {
+		// the first key is not a rotation
+		r.Status.FernetKey = &alpha1.FernetKeyStatus{Rotation: r.Spec.FernetKeyRotation}
+	}
+	stts := r.Status.FernetKey
+	if secret == nil {
+		return "", false, nil
+	}
+	keys := strings.Split(string(secret.Data[fernetKeyKey]), ",")
+	written := secret.Annotations[annotationFernetKeyRotation]
+	if stts.Rotation == r.Spec.FernetKeyRotation && stts.Pending == "" {
+		if len(keys) > 1 && written == stts.Rotation {
+			// the old key of the last rotation was not dropped yet
+			return keys[0], false, nil
+		}
+		return "", false, nil
+	}
+
+	if stts.Pending != r.Spec.FernetKeyRotation {
+		stts.Pending = r.Spec.FernetKeyRotation
+		r.Status.Meta.RemoveCondition(conditionFernetKeyRotationFailed)
+		s.events.Event(r, corev1.EventTypeNormal, common.EventFernetKeyRotationStarted, "new Fernet key added, re-encrypting once all pods run with it")
+	}
+	if written != stts.Pending {
+		// keep all keys of an unfinished rotation, data may be encrypted with any of them
+		return fernetKey + "," + string(secret.Data[fernetKeyKey]), false, nil
+	}
+
+	if job == nil {
+		done, err := rolledOut(s.client, r)
+		return "", err == nil && done, nil
+	}
+	if !common.JobFinished(job) {
+		return "", true, nil
+	}
+	if job.Status.Succeeded == 0 {
+		// keep the failed job for its logs until the rotation is changed
+		if !r.Status.Meta.IsConditionTrue(conditionFernetKeyRotationFailed) {
+			s.events.Event(r, corev1.EventTypeWarning, common.EventFernetKeyRotationFailed, "job "+job.Name+" failed, see its logs")
+		}
+		r.Status.Meta.SetCondition(conditionFernetKeyRotationFailed, "JobFailed", "job "+job.Name+" failed, see its logs")
+		return "", true, nil
+	}
+	if err := deleteJobPods(s.client, job); err != nil {
+		return "", false, err
+	}
+	now := metav1.Now()
+	stts.Rotation = stts.Pending
+	stts.Pending = ""
+	stts.LastRotationTime = &now
+	s.events.Event(r, corev1.EventTypeNormal, common.EventFernetKeyRotated, "metadata DB re-encrypted, old Fernet key dropped")
+	return keys[0], false, nil
+}
+
+// withFernetKeys sets the fernet keys of the observed keys secret and records
+// the rotation they were written for
+func withFernetKeys(observed *corev1.Secret, keys string, stts *alpha1.FernetKeyStatus) func(*reconciler.Object, interface{}) {
+	rotation := stts.Pending
+	if rotation == "" {
+		rotation = stts.Rotation
+	}
+	return func(o *reconciler.Object, v interface{}) {
+		secret := o.Obj.(*k8s.Object).Obj.(*corev1.Secret)
+		secret.Data = map[string][]byte{}
+		for k, v := range observed.Data {
+			secret.Data[k] = v
+		}
+		secret.Data[fernetKeyKey] = []byte(keys)
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[annotationFernetKeyRotation] = rotation
+	}
+}
+
+// job adds the rotation job, its name changes with each rotation
+func (s *Keys) job(r *alpha1.AirflowCluster, dependent []reconciler.Object, rsrclabels map[string]string, bag *k8s.Objects) ([]reconciler.Object, error) {
+	h := sha256.Sum256([]byte(r.Status.FernetKey.Pending))
+	ngdata := templateValue(r, dependent, common.ValueAirflowComponentKeys, rsrclabels, rsrclabels, nil)
+	ngdata.Name = common.RsrcName(r.Name, common.ValueAirflowComponentKeys, "-rotate-"+hex.EncodeToString(h[:])[:8])
+	return bag.WithValue(ngdata).
+		WithTemplate("fernet-rotation-job.yaml", &batchv1.JobList{}, reconciler.NoUpdate, s.jobSpec).
+		Build()
+}
+
+func (s *Keys) jobSpec(o *reconciler.Object, v interface{}) {
+	r := v.(*common.TemplateValue)
+	job := o.Obj.(*k8s.Object).Obj.(*batchv1.Job)
+	sqlSecret := common.RsrcName(r.Cluster.Name, common.ValueAirflowComponentUI, "")
+	keysSecret := common.RsrcName(r.Cluster.Name, common.ValueAirflowComponentKeys, "")
+	// the cli commands use dashes from airflow 2
+	command := []string{"airflow", "rotate_fernet_key"}
+	if !strings.HasPrefix(r.Cluster.Spec.Scheduler.Version, "1.") {
+		command = []string{"airflow", "rotate-fernet-key"}
+	}
+	job.Spec.Template.Spec.Containers[0].Command = command
+	job.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{
+		{Name: "SQL_PASSWORD", ValueFrom: envFromSecret(sqlSecret, "password")},
+		{Name: afc + "SQL_ALCHEMY_CONN", Value: sqlConn(r.Cluster, r.Base, "$(SQL_PASSWORD)")},
+		{Name: afc + "FERNET_KEY", ValueFrom: envFromSecret(keysSecret, fernetKeyKey)},
+	}
+}
+
+// UpdateStatus requeues while a rotation is in progress
+func (s *Keys) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	handlerDone(s.events, rsrc, "Keys", reconciled, err)
+	var period time.Duration
+	r := rsrc.(*alpha1.AirflowCluster)
+	if r.Status.FernetKey != nil && r.Status.FernetKey.Pending != "" &&
+		!r.Status.Meta.IsConditionTrue(conditionFernetKeyRotationFailed) {
+		period = dagJobPollInterval
+	}
+	return period
+}
+
+// ------------------------------ Secrets backend ---------------------------------------
+
+// backendClusters maps a secret labeled as a connection or variable to the
+// clusters in its namespace whose secrets backend selects it. The old and
+// new labels of an updated secret are both mapped so that a secret leaving
+// a backend is dropped from its index.
+func backendClusters(c client.Client) handler.ToRequestsFunc {
+	return func(o handler.MapObject) []reconcile.Request {
+		secretLabels := o.Meta.GetLabels()
+		_, conn := secretLabels[alpha1.LabelSecretConnection]
+		_, variable := secretLabels[alpha1.LabelSecretVariable]
+		if !conn && !variable {
+			return nil
+		}
+		clusters := &alpha1.AirflowClusterList{}
+		if err := c.List(context.TODO(), client.InNamespace(o.Meta.GetNamespace()), clusters); err != nil {
+			log.Printf("%s/%s: listing the clusters of the secret: %v", o.Meta.GetNamespace(), o.Meta.GetName(), err)
+			return nil
+		}
+		requests := []reconcile.Request{}
+		for _, r := range clusters.Items {
+			sb := r.Spec.SecretsBackend
+			if sb == nil {
+				continue
+			}
+			selector := sb.Selector
+			if len(selector) == 0 {
+				selector = map[string]string{alpha1.LabelSecretCluster: r.Name}
+			}
+			if !labels.SelectorFromSet(selector).Matches(labels.Set(secretLabels)) {
+				continue
+			}
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: r.Namespace, Name: r.Name},
+			})
+		}
+		return requests
+	}
+}
+
+// Observables for the secrets backend
+func (s *SecretsBackend) Observables(rsrc interface{}, labels map[string]string, dependent []reconciler.Object) []reconciler.Observable {
+	return k8s.NewObservables().
+		WithLabels(labels).
+		For(&corev1.ConfigMapList{}).
+		For(&corev1.ServiceAccountList{}).
+		For(&rbacv1.RoleList{}).
+		For(&rbacv1.RoleBindingList{}).
+		Get()
+}
+
+// DependentResources - return dependant resources
+func (s *SecretsBackend) DependentResources(rsrc interface{}) []reconciler.Object {
+	return dependantResources(rsrc)
+}
+
+// Objects returns the backend configmap with the secret index and the
+// service account, role and binding that grant access to those secrets
+func (s *SecretsBackend) Objects(rsrc interface{}, rsrclabels map[string]string, observed, dependent, aggregated []reconciler.Object) ([]reconciler.Object, error) {
+	r := rsrc.(*alpha1.AirflowCluster)
+	if r.Spec.SecretsBackend == nil {
+		return []reconciler.Object{}, nil
+	}
+	secrets := &corev1.SecretList{}
+	err := s.client.List(context.TODO(), client.InNamespace(r.Namespace).MatchingLabels(r.Spec.SecretsBackend.Selector), secrets)
+	if err != nil {
+		return []reconciler.Object{}, err
+	}
+	index := map[string]map[string]string{"connections": {}, "variables": {}}
+	names := []string{}
+	for _, secret := range secrets.Items {
+		indexed := false
+		if id, ok := secret.Labels[alpha1.LabelSecretConnection]; ok {
+			index["connections"][id] = secret.Name
+			indexed = true
+		}
+		if key, ok := secret.Labels[alpha1.LabelSecretVariable]; ok {
+			index["variables"][key] = secret.Name
+			indexed = true
+		}
+		if indexed {
+			names = append(names, secret.Name)
+		}
+	}
+	sort.Strings(names)
+	data, err := json.Marshal(index)
+	if err != nil {
+		return []reconciler.Object{}, err
+	}
+
+	ngdata := templateValue(r, dependent, common.ValueAirflowComponentSecrets, rsrclabels, rsrclabels, nil)
+	return k8s.NewObjects().
+		WithValue(ngdata).
+		WithFolder("templates/").
+		WithTemplate("secrets-backend-configmap.yaml", &corev1.ConfigMapList{},
+			func(o *reconciler.Object, v interface{}) {
+				cm := o.Obj.(*k8s.Object).Obj.(*corev1.ConfigMap)
+				cm.Data[secretsBackendIndex] = string(data)
+			}).
+		WithTemplate("serviceaccount.yaml", &corev1.ServiceAccountList{}, reconciler.NoUpdate).
+		WithTemplate("secrets-role.yaml", &rbacv1.RoleList{},
+			func(o *reconciler.Object, v interface{}) {
+				role := o.Obj.(*k8s.Object).Obj.(*rbacv1.Role)
+				// a rule without resourceNames would grant every secret
+				role.Rules = []rbacv1.PolicyRule{}
+				if len(names) != 0 {
+					role.Rules = append(role.Rules, rbacv1.PolicyRule{
+						APIGroups:     []string{""},
+						Resources:     []string{"secrets"},
+						Verbs:         []string{"get"},
+						ResourceNames: names,
+					})
+				}
+			}).
+		WithTemplate("secrets-rolebinding.yaml", &rbacv1.RoleBindingList{}).
+		Build()
+}
+
+// UpdateStatus records the reconcile of the handler, the objects are tracked by Cluster
+func (s *SecretsBackend) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	handlerDone(s.events, rsrc, "SecretsBackend", reconciled, err)
+	return 0
+}
+
+// ------------------------------ DAG validation ---------------------------------------
+
+// dagValidationScript imports the DAGs at the branch head and writes the
+// revision and any import errors to the termination log
+const dagValidationScript = `
+import os
+import sys
+
+rev = os.path.basename(os.path.realpath(os.environ["DAGS_SRC"]))
+if rev.startswith("rev-"):
+    rev = rev[len("rev-"):]
+msg = "rev=%s\n" % rev
+code = 0
+if rev != os.environ.get("VALIDATED_REV"):
+    from airflow.models import DagBag
+    folder = os.environ["AIRFLOW__CORE__DAGS_FOLDER"]
+    bag = DagBag(dag_folder=folder, include_examples=False)
+    for path, err in sorted(bag.import_errors.items()):
+        lines = str(err).strip().splitlines() or [""]
+        msg += "%s: %s\n" % (os.path.relpath(path, folder), lines[-1])
+    code = 1 if bag.import_errors else 0
+with open("/dev/termination-log", "w") as f:
+    f.write(msg[:4000])
+sys.exit(code)
+`
+
+// validatedGit returns the git spec if new commits are gated by validation
+func validatedGit(r *alpha1.AirflowCluster) *alpha1.GitSpec {
+	if r.Spec.DAGs == nil || r.Spec.DAGs.Git == nil || r.Spec.DAGs.Git.Validation == nil {
+		return nil
+	}
+	return r.Spec.DAGs.Git
+}
+
+// gitRev returns the commit the DAGs are pinned to, the last one that passed
+// validation or spec.dags.git.rev. Until the gate passed a first revision,
+// components keep the revision they were last seen running, and follow
+// spec.dags.git.rev when none was seen.
+func gitRev(r *alpha1.AirflowCluster) string {
+	if validatedGit(r) != nil {
+		if v := r.Status.DagValidation; v != nil && v.Revision != "" {
+			return v.Revision
+		}
+		if d := r.Status.DAGs; d != nil && d.Revision != "" {
+			if source, _, _ := dagSource(r.Spec.DAGs); source == d.Source {
+				return d.Revision
+			}
+		}
+	}
+	if r.Spec.DAGs == nil || r.Spec.DAGs.Git == nil {
+		return ""
+	}
+	return r.Spec.DAGs.Git.Rev
+}
+
+// dagsPending returns true while a component that is not deployed yet waits
+// for the first revision to pass validation. A deployed component keeps its
+// StatefulSet at the revision gitRev pins it to.
+func dagsPending(r *alpha1.AirflowCluster, observed []reconciler.Object) bool {
+	if validatedGit(r) == nil || (r.Status.DagValidation != nil && r.Status.DagValidation.Revision != "") {
+		return false
+	}
+	for _, o := range observed {
+		if _, ok := o.Obj.(*k8s.Object).Obj.(*appsv1.StatefulSet); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// deleteJobPods removes the pods of a finished job, they are not garbage
+// collected when the job is deleted
+func deleteJobPods(c client.Client, job *batchv1.Job) error {
+	pods := &corev1.PodList{}
+	err := c.List(context.TODO(), client.InNamespace(job.Namespace).MatchingLabels(map[string]string{"job-name": job.Name}), pods)
+	if err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		if err := c.Delete(context.TODO(), &pods.Items[i]); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Observables for the validation job
+func (s *DagValidation) Observables(rsrc interface{}, labels map[string]string, dependent []reconciler.Object) []reconciler.Observable {
+	return k8s.NewObservables().
+		WithLabels(labels).
+		For(&batchv1.JobList{}).
+		Get()
+}
+
+// DependentResources - return dependant resources
+func (s *DagValidation) DependentResources(rsrc interface{}) []reconciler.Object {
+	return dependantResources(rsrc)
+}
+
+// Objects returns the validation job while a check is due or running.
+// A finished job has its result recorded and is left out so that it is deleted.
+func (s *DagValidation) Objects(rsrc interface{}, rsrclabels map[string]string, observed, dependent, aggregated []reconciler.Object) ([]reconciler.Object, error) {
+	r := rsrc.(*alpha1.AirflowCluster)
+	git := validatedGit(r)
+	if git == nil {
+		r.Status.DagValidation = nil
+		r.Status.Meta.RemoveCondition(conditionDagValidationFailed)
+		return []reconciler.Object{}, nil
+	}
+	if r.Status.DagValidation == nil {
+		r.Status.DagValidation = &alpha1.DagValidationStatus{}
+	}
+	stts := r.Status.DagValidation
+
+	for _, o := range observed {
+		job, ok := o.Obj.(*k8s.Object).Obj.(*batchv1.Job)
+		if !ok {
+			continue
+		}
+		if !common.JobFinished(job) {
+			return s.job(r, dependent, rsrclabels)
+		}
+		return []reconciler.Object{}, s.recordResult(r, job)
+	}
+
+	interval := time.Duration(git.Validation.Interval) * time.Second
+	if stts.LastCheckTime != nil && time.Since(stts.LastCheckTime.Time) < interval {
+		return []reconciler.Object{}, nil
+	}
+	now := metav1.Now()
+	stts.LastCheckTime = &now
+	return s.job(r, dependent, rsrclabels)
+}
+
+func (s *DagValidation) job(r *alpha1.AirflowCluster, dependent []reconciler.Object, rsrclabels map[string]string) ([]reconciler.Object, error) {
+	ngdata := templateValue(r, dependent, common.ValueAirflowComponentDagCheck, rsrclabels, rsrclabels, nil)
+	return k8s.NewObjects().
+		WithValue(ngdata).
+		WithTemplate("dag-validation-job.yaml", &batchv1.JobList{}, reconciler.NoUpdate, s.jobSpec).
+		Build()
+}
+
+func (s *DagValidation) jobSpec(o *reconciler.Object, v interface{}) {
+	r := v.(*common.TemplateValue)
+	job := o.Obj.(*k8s.Object).Obj.(*batchv1.Job)
+	spec := &job.Spec.Template.Spec
+
+	// clone the branch head instead of the pinned revision
+	git := r.Cluster.Spec.DAGs.Git.DeepCopy()
+	git.Rev = ""
+	git.Once = true
+	_, gc := gitContainer(git, "dags-data")
+	spec.InitContainers = append(spec.InitContainers, gc)
+	addGitSecretVolume(spec, git, gitSecretVolName)
+
+	spec.Containers[0].Args = []string{dagValidationScript}
+	spec.Containers[0].Resources = git.Validation.Resources
+	spec.Containers[0].Env = []corev1.EnvVar{
+		{Name: afc + "DAGS_FOLDER", Value: airflowDagsBase + gitSyncDestDir + "/" + r.Cluster.Spec.DAGs.DagSubdir},
+		{Name: afc + "LOAD_EXAMPLES", Value: "False"},
+		{Name: "DAGS_SRC", Value: airflowDagsBase + gitSyncDestDir},
+		{Name: "VALIDATED_REV", Value: r.Cluster.Status.DagValidation.Revision},
+	}
+	// DAGs may import from the plugins and requirements
+	addPlugins(r.Cluster, spec)
+}
+
+// recordResult reads the outcome of a finished validation job into the
+// status and removes the job pods, which are not garbage collected when
+// the job is deleted
+func (s *DagValidation) recordResult(r *alpha1.AirflowCluster, job *batchv1.Job) error {
+	pods := &corev1.PodList{}
+	err := s.client.List(context.TODO(), client.InNamespace(job.Namespace).MatchingLabels(map[string]string{"job-name": job.Name}), pods)
+	if err != nil {
+		return err
+	}
+	message := ""
+	for i := range pods.Items {
+		for _, cs := range pods.Items[i].Status.ContainerStatuses {
+			if cs.Name == "validate" && cs.State.Terminated != nil {
+				message = cs.State.Terminated.Message
+			}
+		}
+	}
+	if err := deleteJobPods(s.client, job); err != nil {
+		return err
+	}
+
+	rev := ""
+	details := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(message), "\n") {
+		if strings.HasPrefix(line, "rev=") {
+			rev = strings.TrimPrefix(line, "rev=")
+		} else if line != "" {
+			details = append(details, line)
+		}
+	}
+
+	stts := r.Status.DagValidation
+	if job.Status.Succeeded > 0 && rev != "" {
+		if rev != stts.Revision {
+			s.events.Eventf(r, corev1.EventTypeNormal, common.EventDagRevisionValidated, "DAG revision %s passed validation and is being rolled out", rev)
+			stts.Revision = rev
+		}
+		stts.Candidate = rev
+		r.Status.Meta.ClearCondition(conditionDagValidationFailed, "ValidationPassed", "DAG revision "+rev+" passed validation")
+		return nil
+	}
+
+	msg := "validation job " + job.Name + " failed, see its logs"
+	if rev != "" {
+		msg = "DAG revision " + rev + " failed validation: " + strings.Join(details, "; ")
+	}
+	if rev != stts.Candidate || !r.Status.Meta.IsConditionTrue(conditionDagValidationFailed) {
+		s.events.Event(r, corev1.EventTypeWarning, common.EventDagValidationFailed, msg)
+	}
+	stts.Candidate = rev
+	r.Status.Meta.SetCondition(conditionDagValidationFailed, "ValidationFailed", msg)
+	return nil
+}
+
+// UpdateStatus requeues while the job runs and when the next check is due
+func (s *DagValidation) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	handlerDone(s.events, rsrc, "DagValidation", reconciled, err)
+	var period time.Duration
+	r := rsrc.(*alpha1.AirflowCluster)
+	git := validatedGit(r)
+	if git == nil || r.Status.DagValidation == nil {
+		return period
+	}
+	if len(reconciled) != 0 {
+		return dagJobPollInterval
+	}
+	period = dagJobPollInterval
+	if last := r.Status.DagValidation.LastCheckTime; last != nil {
+		if next := time.Duration(git.Validation.Interval)*time.Second - time.Since(last.Time); next > period {
+			period = next
+		}
+	}
+	return period
+}
+
 // ------------------------------ MemoryStore ---------------------------------------
 
 // DependentResources - return dependant resources
@@ -852,15 +3075,25 @@ func (s *MemoryStore) Objects(rsrc interface{}, rsrclabels map[string]string, ob
 
 // UpdateStatus - update status block
 func (s *MemoryStore) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	handlerDone(s.events, rsrc, "MemoryStore", reconciled, err)
 	var period time.Duration
 	r := rsrc.(*alpha1.AirflowCluster)
+	ctype := common.ComponentCondition("MemoryStore")
 	if r.Spec.MemoryStore == nil {
+		r.Status.Meta.RemoveCondition(ctype)
 		return period
 	}
 	stts := &r.Spec.MemoryStore.Status
 	ready := false
 	if len(reconciled) != 0 {
 		instance := reconciled[0].Obj.(*redis.Object).Redis
+		if reconciled[0].Lifecycle != "" {
+			s.events.Eventf(r, corev1.EventTypeNormal, common.EventMemoryStoreProvisioning,
+				"provisioning MemoryStore instance %s", instance.Name)
+		} else if stts.State != "READY" && instance.State == "READY" {
+			s.events.Eventf(r, corev1.EventTypeNormal, common.EventMemoryStoreReady,
+				"MemoryStore instance %s is ready", instance.Name)
+		}
 		stts.CreateTime = instance.CreateTime
 		stts.CurrentLocationID = instance.CurrentLocationId
 		stts.Host = instance.Host
@@ -876,6 +3109,14 @@ func (s *MemoryStore) UpdateStatus(rsrc interface{}, reconciled []reconciler.Obj
 		period = time.Second * 30
 		stts.Meta.UpdateStatus(&ready, err)
 	}
+	switch {
+	case err != nil:
+		r.Status.Meta.ClearCondition(ctype, common.ReasonErrorSeen, err.Error())
+	case stts.State == "READY" || stts.State == "MAINTENANCE":
+		r.Status.Meta.SetCondition(ctype, common.ReasonComponentReady, "MemoryStore instance is "+stts.State)
+	default:
+		r.Status.Meta.ClearCondition(ctype, common.ReasonComponentNotReady, "MemoryStore instance is "+stts.State)
+	}
 	return period
 }
 
@@ -894,6 +3135,8 @@ func (s *MemoryStore) Finalize(rsrc interface{}, observed, dependent []reconcile
 	obj.Status.NotReady("Finalizing", "Finalizing in progress")
 	if len(observed) != 0 {
 		finalizer.Add(r, finalizer.Cleanup)
+		s.events.Eventf(r, corev1.EventTypeWarning, common.EventFinalizationBlocked,
+			"waiting for MemoryStore instance %s to be deleted", observed[0].Obj.(*redis.Object).Redis.Name)
 		items := observed
 		for i := range items {
 			items[i].Delete = true
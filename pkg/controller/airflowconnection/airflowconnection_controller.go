@@ -0,0 +1,196 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package airflowconnection
+
+import (
+	"context"
+	alpha1 "k8s.io/airflow-operator/pkg/apis/airflow/v1alpha1"
+	"k8s.io/airflow-operator/pkg/controller/common"
+	"k8s.io/airflow-operator/pkg/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-reconciler/pkg/finalizer"
+	gr "sigs.k8s.io/controller-reconciler/pkg/genericreconciler"
+	"sigs.k8s.io/controller-reconciler/pkg/reconciler"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"time"
+)
+
+const (
+	syncInterval    = 5 * time.Minute
+	retryInterval   = time.Minute
+	jobPollInterval = 10 * time.Second
+)
+
+// +kubebuilder:rbac:groups=airflow.k8s.io,resources=airflowconnections,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=,resources=pods,verbs=get;list;watch
+
+// Add creates a new AirflowConnection Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	r := newReconciler(mgr)
+	return r.Controller(metrics.NewReconciler("AirflowConnection", r))
+}
+
+func newReconciler(mgr manager.Manager) *gr.Reconciler {
+	return gr.
+		WithManager(mgr).
+		For(&alpha1.AirflowConnection{}, alpha1.SchemeGroupVersion).
+		Using(&Connection{
+			client: mgr.GetClient(),
+			db:     common.NewMetadataDB(mgr.GetClient()),
+			events: common.NewEvents(mgr.GetRecorder("airflowconnection-controller")),
+		}).
+		WithErrorHandler(handleError).
+		WithValidator(validate).
+		WithDefaulter(applyDefaults).
+		Build()
+}
+
+func handleError(resource interface{}, err error, errkind string) {
+	ac := resource.(*alpha1.AirflowConnection)
+	if err != nil {
+		metrics.ReconcileError("AirflowConnection", ac, errkind)
+		ac.Status.SetError("ErrorSeen", err.Error())
+	} else {
+		ac.Status.ClearError()
+	}
+}
+
+func validate(resource interface{}) error {
+	ac := resource.(*alpha1.AirflowConnection)
+	return ac.Validate()
+}
+
+func applyDefaults(resource interface{}) {
+	ac := resource.(*alpha1.AirflowConnection)
+	ac.ApplyDefaults()
+}
+
+// Connection - interface to sync the connection into the metadata DB
+type Connection struct {
+	client client.Client
+	db     *common.MetadataDB
+	events *common.Events
+}
+
+// row returns the connection table row for the spec and the version it was built from
+func (s *Connection) row(r *alpha1.AirflowConnection) (*common.MetadataRow, string, error) {
+	row := &common.MetadataRow{
+		Model: "Connection",
+		Key:   "conn_id",
+		ID:    r.Spec.ConnID,
+		Fields: map[string]interface{}{
+			"conn_type": r.Spec.ConnType,
+			"host":      r.Spec.Host,
+			"port":      nil,
+			"schema":    r.Spec.Schema,
+			"login":     r.Spec.Login,
+			"password":  "",
+			"extra":     r.Spec.Extra,
+		},
+	}
+	if r.Spec.Port != 0 {
+		row.Fields["port"] = r.Spec.Port
+	}
+	// the credentials are read by the sync Job, the secret version is part
+	// of the version so that a changed secret is synced again
+	secrets := []*corev1.Secret{}
+	if r.Spec.SecretRef != nil {
+		secret := &corev1.Secret{}
+		err := s.client.Get(context.TODO(), client.ObjectKey{Namespace: r.Namespace, Name: r.Spec.SecretRef.Name}, secret)
+		if err != nil {
+			return nil, "", err
+		}
+		row.FromSecret("login", secret.Name, "login", true)
+		row.FromSecret("password", secret.Name, "password", true)
+		secrets = append(secrets, secret)
+	}
+	version, err := common.SyncVersion(r.Spec, secrets...)
+	return row, version, err
+}
+
+// Observables - the connection lives in the metadata DB, there is nothing to observe
+func (s *Connection) Observables(rsrc interface{}, labels map[string]string, dependent []reconciler.Object) []reconciler.Observable {
+	return []reconciler.Observable{}
+}
+
+// Objects writes the connection to the metadata DB of the cluster when it
+// changed or the drift check is due. The sync Job is run through the
+// MetadataDB, the handler returns no kubernetes objects.
+func (s *Connection) Objects(rsrc interface{}, rsrclabels map[string]string, observed, dependent, aggregated []reconciler.Object) ([]reconciler.Object, error) {
+	r := rsrc.(*alpha1.AirflowConnection)
+	if r.DeletionTimestamp != nil {
+		return []reconciler.Object{}, nil
+	}
+	row, version, err := s.row(r)
+	if err != nil {
+		return nil, err
+	}
+	stts := &r.Status.MetadataSyncStatus
+	if !common.SyncDue(stts, version, syncInterval) {
+		return []reconciler.Object{}, nil
+	}
+	if stts.SyncedVersion != version {
+		stts.NotReady("Syncing", "writing connection "+r.Spec.ConnID+" to the metadata DB")
+	}
+	_, drifted, err := s.db.Sync(r, "AirflowConnection", syncJobName(r), r.Spec.AirflowClusterRef.Name, row, version, stts)
+	if err != nil {
+		return nil, err
+	}
+	if drifted {
+		s.events.Eventf(r, corev1.EventTypeWarning, common.EventDrift,
+			"connection %s was changed in the metadata DB of %s and has been restored", r.Spec.ConnID, r.Spec.AirflowClusterRef.Name)
+	}
+	return []reconciler.Object{}, nil
+}
+
+// UpdateStatus polls the sync Job while it runs and requeues to detect
+// drift in the metadata DB
+func (s *Connection) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	metrics.HandlerDone("AirflowConnection", rsrc.(*alpha1.AirflowConnection), "Connection", err)
+	stts := &rsrc.(*alpha1.AirflowConnection).Status
+	if err != nil {
+		stts.NotReady("SyncFailed", err.Error())
+		return retryInterval
+	}
+	stts.ClearError()
+	if stts.SyncJob != "" {
+		return jobPollInterval
+	}
+	stts.Ready("Synced", "connection matches the metadata DB")
+	return syncInterval
+}
+
+// Finalize removes the connection from the metadata DB. The finalizer stays
+// until the removal Job finished.
+func (s *Connection) Finalize(rsrc interface{}, observed, dependent []reconciler.Object) error {
+	r := rsrc.(*alpha1.AirflowConnection)
+	done, err := s.db.Remove(r, "AirflowConnection", syncJobName(r), r.Spec.AirflowClusterRef.Name, "Connection", "conn_id", r.Spec.ConnID, r.Status.SyncedID)
+	if err != nil || !done {
+		return err
+	}
+	metrics.Forget("AirflowConnection", r)
+	finalizer.RemoveStandard(r)
+	return nil
+}
+
+// syncJobName returns the name of the Job writing the connection
+func syncJobName(r *alpha1.AirflowConnection) string {
+	return common.RsrcName(r.Name, "connection-sync", "")
+}
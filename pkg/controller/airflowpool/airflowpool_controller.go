@@ -0,0 +1,169 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package airflowpool
+
+import (
+	alpha1 "k8s.io/airflow-operator/pkg/apis/airflow/v1alpha1"
+	"k8s.io/airflow-operator/pkg/controller/common"
+	"k8s.io/airflow-operator/pkg/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-reconciler/pkg/finalizer"
+	gr "sigs.k8s.io/controller-reconciler/pkg/genericreconciler"
+	"sigs.k8s.io/controller-reconciler/pkg/reconciler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"time"
+)
+
+const (
+	syncInterval    = 5 * time.Minute
+	retryInterval   = time.Minute
+	jobPollInterval = 10 * time.Second
+	// poolModel and poolKey identify the row of a pool
+	poolModel = "Pool"
+	poolKey   = "pool"
+)
+
+// +kubebuilder:rbac:groups=airflow.k8s.io,resources=airflowpools,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=,resources=pods,verbs=get;list;watch
+
+// Add creates a new AirflowPool Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	r := newReconciler(mgr)
+	return r.Controller(metrics.NewReconciler("AirflowPool", r))
+}
+
+func newReconciler(mgr manager.Manager) *gr.Reconciler {
+	return gr.
+		WithManager(mgr).
+		For(&alpha1.AirflowPool{}, alpha1.SchemeGroupVersion).
+		Using(&Pool{
+			db:     common.NewMetadataDB(mgr.GetClient()),
+			events: common.NewEvents(mgr.GetRecorder("airflowpool-controller")),
+		}).
+		WithErrorHandler(handleError).
+		WithValidator(validate).
+		WithDefaulter(applyDefaults).
+		Build()
+}
+
+func handleError(resource interface{}, err error, errkind string) {
+	ap := resource.(*alpha1.AirflowPool)
+	if err != nil {
+		metrics.ReconcileError("AirflowPool", ap, errkind)
+		ap.Status.SetError("ErrorSeen", err.Error())
+	} else {
+		ap.Status.ClearError()
+	}
+}
+
+func validate(resource interface{}) error {
+	ap := resource.(*alpha1.AirflowPool)
+	return ap.Validate()
+}
+
+func applyDefaults(resource interface{}) {
+	ap := resource.(*alpha1.AirflowPool)
+	ap.ApplyDefaults()
+}
+
+// Pool - interface to sync the pool into the metadata DB
+type Pool struct {
+	db     *common.MetadataDB
+	events *common.Events
+}
+
+// Observables - the pool lives in the metadata DB, there is nothing to observe
+func (s *Pool) Observables(rsrc interface{}, labels map[string]string, dependent []reconciler.Object) []reconciler.Observable {
+	return []reconciler.Observable{}
+}
+
+// Objects writes the pool to the metadata DB of the cluster when it
+// changed or the drift check is due. The sync Job is run through the
+// MetadataDB, the handler returns no kubernetes objects.
+func (s *Pool) Objects(rsrc interface{}, rsrclabels map[string]string, observed, dependent, aggregated []reconciler.Object) ([]reconciler.Object, error) {
+	r := rsrc.(*alpha1.AirflowPool)
+	if r.DeletionTimestamp != nil {
+		return []reconciler.Object{}, nil
+	}
+	row := &common.MetadataRow{
+		Model: poolModel,
+		Key:   poolKey,
+		ID:    r.Spec.Pool,
+		Fields: map[string]interface{}{
+			"slots":       r.Spec.Slots,
+			"description": r.Spec.Description,
+		},
+	}
+	version, err := common.SyncVersion(r.Spec)
+	if err != nil {
+		return nil, err
+	}
+	stts := &r.Status.MetadataSyncStatus
+	if !common.SyncDue(stts, version, syncInterval) {
+		return []reconciler.Object{}, nil
+	}
+	if stts.SyncedVersion != version {
+		stts.NotReady("Syncing", "writing pool "+r.Spec.Pool+" to the metadata DB")
+	}
+	_, drifted, err := s.db.Sync(r, "AirflowPool", syncJobName(r), r.Spec.AirflowClusterRef.Name, row, version, stts)
+	if err != nil {
+		return nil, err
+	}
+	if drifted {
+		s.events.Eventf(r, corev1.EventTypeWarning, common.EventDrift,
+			"pool %s was changed in the metadata DB of %s and has been restored", r.Spec.Pool, r.Spec.AirflowClusterRef.Name)
+	}
+	return []reconciler.Object{}, nil
+}
+
+// UpdateStatus polls the sync Job while it runs and requeues to detect
+// drift in the metadata DB
+func (s *Pool) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	metrics.HandlerDone("AirflowPool", rsrc.(*alpha1.AirflowPool), "Pool", err)
+	stts := &rsrc.(*alpha1.AirflowPool).Status
+	if err != nil {
+		stts.NotReady("SyncFailed", err.Error())
+		return retryInterval
+	}
+	stts.ClearError()
+	if stts.SyncJob != "" {
+		return jobPollInterval
+	}
+	stts.Ready("Synced", "pool matches the metadata DB")
+	return syncInterval
+}
+
+// Finalize removes the pool from the metadata DB, also under the name last
+// synced in case the name was changed since. The finalizer stays until the
+// removal Job finished.
+func (s *Pool) Finalize(rsrc interface{}, observed, dependent []reconciler.Object) error {
+	r := rsrc.(*alpha1.AirflowPool)
+	done, err := s.db.Remove(r, "AirflowPool", syncJobName(r), r.Spec.AirflowClusterRef.Name, poolModel, poolKey, r.Spec.Pool, r.Status.SyncedID)
+	if err != nil || !done {
+		return err
+	}
+	metrics.Forget("AirflowPool", r)
+	finalizer.RemoveStandard(r)
+	return nil
+}
+
+// syncJobName returns the name of the Job writing the pool
+func syncJobName(r *alpha1.AirflowPool) string {
+	return common.RsrcName(r.Name, "pool-sync", "")
+}
@@ -0,0 +1,198 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package airflowvariable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	alpha1 "k8s.io/airflow-operator/pkg/apis/airflow/v1alpha1"
+	"k8s.io/airflow-operator/pkg/controller/common"
+	"k8s.io/airflow-operator/pkg/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-reconciler/pkg/finalizer"
+	gr "sigs.k8s.io/controller-reconciler/pkg/genericreconciler"
+	"sigs.k8s.io/controller-reconciler/pkg/reconciler"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"time"
+)
+
+const (
+	syncInterval    = 5 * time.Minute
+	retryInterval   = time.Minute
+	jobPollInterval = 10 * time.Second
+	// variableModel and variableKey identify the row of a variable
+	variableModel = "Variable"
+	variableKey   = "key"
+)
+
+// +kubebuilder:rbac:groups=airflow.k8s.io,resources=airflowvariables,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=,resources=pods,verbs=get;list;watch
+
+// Add creates a new AirflowVariable Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	r := newReconciler(mgr)
+	return r.Controller(metrics.NewReconciler("AirflowVariable", r))
+}
+
+func newReconciler(mgr manager.Manager) *gr.Reconciler {
+	return gr.
+		WithManager(mgr).
+		For(&alpha1.AirflowVariable{}, alpha1.SchemeGroupVersion).
+		Using(&Variable{
+			client: mgr.GetClient(),
+			db:     common.NewMetadataDB(mgr.GetClient()),
+			events: common.NewEvents(mgr.GetRecorder("airflowvariable-controller")),
+		}).
+		WithErrorHandler(handleError).
+		WithValidator(validate).
+		WithDefaulter(applyDefaults).
+		Build()
+}
+
+func handleError(resource interface{}, err error, errkind string) {
+	av := resource.(*alpha1.AirflowVariable)
+	if err != nil {
+		metrics.ReconcileError("AirflowVariable", av, errkind)
+		av.Status.SetError("ErrorSeen", err.Error())
+	} else {
+		av.Status.ClearError()
+	}
+}
+
+func validate(resource interface{}) error {
+	av := resource.(*alpha1.AirflowVariable)
+	return av.Validate()
+}
+
+func applyDefaults(resource interface{}) {
+	av := resource.(*alpha1.AirflowVariable)
+	av.ApplyDefaults()
+}
+
+// Variable - interface to sync the variable into the metadata DB
+type Variable struct {
+	client client.Client
+	db     *common.MetadataDB
+	events *common.Events
+}
+
+// row returns the variable table row for the spec and the version it was built from
+func (s *Variable) row(r *alpha1.AirflowVariable) (*common.MetadataRow, string, error) {
+	row := &common.MetadataRow{
+		Model:  variableModel,
+		Key:    variableKey,
+		ID:     r.Spec.Key,
+		Fields: map[string]interface{}{"val": r.Spec.Value},
+	}
+	// the value of a secret is read by the sync Job, the secret is checked
+	// here and its version is part of the version so that a changed secret
+	// is synced again
+	secrets := []*corev1.Secret{}
+	if ref := r.Spec.SecretRef; ref != nil {
+		secret := &corev1.Secret{}
+		err := s.client.Get(context.TODO(), client.ObjectKey{Namespace: r.Namespace, Name: ref.Name}, secret)
+		if err != nil {
+			return nil, "", err
+		}
+		v, ok := secret.Data[ref.Key]
+		if !ok {
+			return nil, "", fmt.Errorf("key %s missing in secret %s", ref.Key, ref.Name)
+		}
+		if r.Spec.JSON && !json.Valid(v) {
+			return nil, "", fmt.Errorf("key %s of secret %s is not json", ref.Key, ref.Name)
+		}
+		row.FromSecret("val", ref.Name, ref.Key, false)
+		secrets = append(secrets, secret)
+	}
+	version, err := common.SyncVersion(r.Spec, secrets...)
+	return row, version, err
+}
+
+// Observables - the variable lives in the metadata DB, there is nothing to observe
+func (s *Variable) Observables(rsrc interface{}, labels map[string]string, dependent []reconciler.Object) []reconciler.Observable {
+	return []reconciler.Observable{}
+}
+
+// Objects writes the variable to the metadata DB of the cluster when it
+// changed or the drift check is due. The sync Job is run through the
+// MetadataDB, the handler returns no kubernetes objects.
+func (s *Variable) Objects(rsrc interface{}, rsrclabels map[string]string, observed, dependent, aggregated []reconciler.Object) ([]reconciler.Object, error) {
+	r := rsrc.(*alpha1.AirflowVariable)
+	if r.DeletionTimestamp != nil {
+		return []reconciler.Object{}, nil
+	}
+	row, version, err := s.row(r)
+	if err != nil {
+		return nil, err
+	}
+	stts := &r.Status.MetadataSyncStatus
+	if !common.SyncDue(stts, version, syncInterval) {
+		return []reconciler.Object{}, nil
+	}
+	if stts.SyncedVersion != version {
+		stts.NotReady("Syncing", "writing variable "+r.Spec.Key+" to the metadata DB")
+	}
+	_, drifted, err := s.db.Sync(r, "AirflowVariable", syncJobName(r), r.Spec.AirflowClusterRef.Name, row, version, stts)
+	if err != nil {
+		return nil, err
+	}
+	if drifted {
+		s.events.Eventf(r, corev1.EventTypeWarning, common.EventDrift,
+			"variable %s was changed in the metadata DB of %s and has been restored", r.Spec.Key, r.Spec.AirflowClusterRef.Name)
+	}
+	return []reconciler.Object{}, nil
+}
+
+// UpdateStatus polls the sync Job while it runs and requeues to detect
+// drift in the metadata DB
+func (s *Variable) UpdateStatus(rsrc interface{}, reconciled []reconciler.Object, err error) time.Duration {
+	metrics.HandlerDone("AirflowVariable", rsrc.(*alpha1.AirflowVariable), "Variable", err)
+	stts := &rsrc.(*alpha1.AirflowVariable).Status
+	if err != nil {
+		stts.NotReady("SyncFailed", err.Error())
+		return retryInterval
+	}
+	stts.ClearError()
+	if stts.SyncJob != "" {
+		return jobPollInterval
+	}
+	stts.Ready("Synced", "variable matches the metadata DB")
+	return syncInterval
+}
+
+// Finalize removes the variable from the metadata DB, also under the key
+// last synced in case the key was changed since. The finalizer stays until the
+// removal Job finished.
+func (s *Variable) Finalize(rsrc interface{}, observed, dependent []reconciler.Object) error {
+	r := rsrc.(*alpha1.AirflowVariable)
+	done, err := s.db.Remove(r, "AirflowVariable", syncJobName(r), r.Spec.AirflowClusterRef.Name, variableModel, variableKey, r.Spec.Key, r.Status.SyncedID)
+	if err != nil || !done {
+		return err
+	}
+	metrics.Forget("AirflowVariable", r)
+	finalizer.RemoveStandard(r)
+	return nil
+}
+
+// syncJobName returns the name of the Job writing the variable
+func syncJobName(r *alpha1.AirflowVariable) string {
+	return common.RsrcName(r.Name, "variable-sync", "")
+}
@@ -0,0 +1,54 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+func TestCertValid(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	hosts := []string{"af-ui.default.svc", "airflow.example.com"}
+	cert, key, err := SelfSignedCert(hosts, 90*24*time.Hour)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(key).NotTo(gomega.BeEmpty())
+
+	tests := []struct {
+		name        string
+		cert        []byte
+		hosts       []string
+		renewBefore time.Duration
+		valid       bool
+	}{
+		{"valid", cert, hosts, 30 * 24 * time.Hour, true},
+		{"hosts in another order", cert, []string{hosts[1], hosts[0]}, 0, true},
+		{"due for renewal", cert, hosts, 91 * 24 * time.Hour, false},
+		{"host added", cert, append(hosts, "other.example.com"), 0, false},
+		{"host removed", cert, hosts[:1], 0, false},
+		{"not pem", []byte("garbage"), hosts, 0, false},
+		{"empty", nil, hosts, 0, false},
+		{"key instead of cert", key, hosts, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(CertValid(tt.cert, tt.hosts, tt.renewBefore)).To(gomega.Equal(tt.valid))
+		})
+	}
+}
@@ -49,6 +49,11 @@ const (
 	ValueAirflowComponentScheduler   = "scheduler"
 	ValueAirflowComponentWorker      = "worker"
 	ValueAirflowComponentFlower      = "flower"
+	ValueAirflowComponentDagCheck    = "dagcheck"
+	ValueAirflowComponentLogs        = "logs"
+	ValueAirflowComponentSecrets     = "secrets"
+	ValueAirflowComponentKeys        = "keys"
+	ValueAirflowComponentStatsd      = "statsd"
 	ValueSQLProxyTypeMySQL           = "mysql"
 	ValueSQLProxyTypePostgres        = "postgres"
 	LabelApp                         = "app"
@@ -103,6 +108,9 @@ type TemplateValue struct {
 	PDBMinAvail string
 	Expected    []reconciler.Object
 	SQLConn     string
+	LogConn     string
+	NFSServer   string
+	ConfigHash  string
 }
 
 // differs returns true if the resource needs to be updated
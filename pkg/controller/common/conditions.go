@@ -0,0 +1,73 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-reconciler/pkg/reconciler"
+	"sigs.k8s.io/controller-reconciler/pkg/reconciler/manager/k8s"
+	"sigs.k8s.io/controller-reconciler/pkg/status"
+	"strings"
+)
+
+// Reasons of the component conditions
+const (
+	ReasonComponentReady    = "ComponentReady"
+	ReasonComponentNotReady = "ComponentNotReady"
+	ReasonErrorSeen         = "ErrorSeen"
+	ReasonSecretMissing     = "SecretMissing"
+)
+
+// ComponentCondition returns the type of the condition with the readiness
+// of a component e.g. SchedulerReady
+func ComponentCondition(component string) status.ConditionType {
+	return status.ConditionType(component + "Ready")
+}
+
+// UpdateComponentCondition sets the readiness condition of component from
+// the objects its handler reconciled and the error it saw. The condition is
+// removed when the component is not enabled in the spec.
+func UpdateComponentCondition(meta *status.Meta, component string, enabled bool, reconciled []reconciler.Object, err error) {
+	ctype := ComponentCondition(component)
+	if !enabled {
+		meta.RemoveCondition(ctype)
+		return
+	}
+	if err != nil {
+		meta.ClearCondition(ctype, ReasonErrorSeen, err.Error())
+		return
+	}
+	notready := []string{}
+	for _, item := range reconciler.ObjectsByType(reconciled, k8s.Type) {
+		sts, ok := item.Obj.(*k8s.Object).Obj.(*appsv1.StatefulSet)
+		if !ok {
+			continue
+		}
+		switch {
+		case item.Lifecycle != "":
+			notready = append(notready, fmt.Sprintf("StatefulSet %s was created", sts.Name))
+		case !stsReady(sts):
+			notready = append(notready, fmt.Sprintf("StatefulSet %s has %d of %d replicas ready",
+				sts.Name, sts.Status.ReadyReplicas, *sts.Spec.Replicas))
+		}
+	}
+	if len(notready) != 0 {
+		meta.ClearCondition(ctype, ReasonComponentNotReady, strings.Join(notready, ", "))
+		return
+	}
+	meta.SetCondition(ctype, ReasonComponentReady, component+" is ready")
+}
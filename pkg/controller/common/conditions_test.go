@@ -0,0 +1,75 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-reconciler/pkg/reconciler"
+	"sigs.k8s.io/controller-reconciler/pkg/reconciler/manager/k8s"
+	"sigs.k8s.io/controller-reconciler/pkg/status"
+)
+
+func stsObject(name string, ready int32, lifecycle string) reconciler.Object {
+	replicas := int32(2)
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: ready, CurrentReplicas: ready},
+	}
+	return reconciler.Object{Type: k8s.Type, Lifecycle: lifecycle, Obj: &k8s.Object{Obj: sts}}
+}
+
+func TestUpdateComponentCondition(t *testing.T) {
+	ctype := ComponentCondition("Scheduler")
+	svc := reconciler.Object{Type: k8s.Type, Obj: &k8s.Object{Obj: &corev1.Service{}}}
+	tests := []struct {
+		name       string
+		enabled    bool
+		reconciled []reconciler.Object
+		err        error
+		status     corev1.ConditionStatus
+		reason     string
+	}{
+		{"disabled", false, []reconciler.Object{stsObject("s", 2, "")}, nil, "", ""},
+		{"ready", true, []reconciler.Object{svc, stsObject("s", 2, "")}, nil, corev1.ConditionTrue, ReasonComponentReady},
+		{"no statefulset", true, []reconciler.Object{svc}, nil, corev1.ConditionTrue, ReasonComponentReady},
+		{"created", true, []reconciler.Object{stsObject("s", 2, reconciler.LifecycleManaged)}, nil, corev1.ConditionFalse, ReasonComponentNotReady},
+		{"replicas not ready", true, []reconciler.Object{stsObject("s", 1, "")}, nil, corev1.ConditionFalse, ReasonComponentNotReady},
+		{"error", true, []reconciler.Object{stsObject("s", 2, "")}, errors.New("boom"), corev1.ConditionFalse, ReasonErrorSeen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			meta := &status.Meta{}
+			meta.SetCondition(ctype, ReasonComponentReady, "Scheduler is ready")
+			UpdateComponentCondition(meta, "Scheduler", tt.enabled, tt.reconciled, tt.err)
+			cond := meta.GetCondition(ctype)
+			if tt.status == "" {
+				g.Expect(cond).To(gomega.BeNil())
+				return
+			}
+			g.Expect(cond).NotTo(gomega.BeNil())
+			g.Expect(cond.Status).To(gomega.Equal(tt.status))
+			g.Expect(cond.Reason).To(gomega.Equal(tt.reason))
+		})
+	}
+}
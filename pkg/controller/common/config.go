@@ -0,0 +1,78 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+)
+
+func sortedKeys(m map[string]string) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// MergeConfig overrides the keys of an ini formatted airflow.cfg with the
+// given sections. Keys missing in a section are added at its end and
+// missing sections are added at the end of the config.
+func MergeConfig(config string, sections map[string]map[string]string) string {
+	var buf bytes.Buffer
+	seen := map[string]map[string]bool{"": {}}
+	section := ""
+	addMissing := func() {
+		for _, k := range sortedKeys(sections[section]) {
+			if !seen[section][k] {
+				buf.WriteString(k + " = " + sections[section][k] + "\n")
+			}
+		}
+	}
+	for _, line := range strings.Split(strings.TrimRight(config, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			addMissing()
+			section = strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			seen[section] = map[string]bool{}
+		} else if i := strings.Index(trimmed, "="); i > 0 && !strings.HasPrefix(trimmed, "#") {
+			key := strings.TrimSpace(trimmed[:i])
+			if value, ok := sections[section][key]; ok {
+				line = key + " = " + value
+				seen[section][key] = true
+			}
+		}
+		buf.WriteString(line + "\n")
+	}
+	addMissing()
+
+	names := []string{}
+	for name := range sections {
+		if _, ok := seen[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		buf.WriteString("\n[" + name + "]\n")
+		for _, k := range sortedKeys(sections[name]) {
+			buf.WriteString(k + " = " + sections[name][k] + "\n")
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,59 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestMergeConfig(t *testing.T) {
+	config := "[core]\ndags_folder = /dags\n# parallelism = 4\nparallelism = 32\n\n[webserver]\nexpose_config = False\n"
+	tests := []struct {
+		name     string
+		sections map[string]map[string]string
+		want     string
+	}{
+		{"no sections", nil, config},
+		{
+			"override",
+			map[string]map[string]string{"core": {"parallelism": "8"}},
+			"[core]\ndags_folder = /dags\n# parallelism = 4\nparallelism = 8\n\n[webserver]\nexpose_config = False\n",
+		},
+		{
+			"missing key",
+			map[string]map[string]string{"core": {"load_examples": "False", "dags_folder": "/git"}},
+			"[core]\ndags_folder = /git\n# parallelism = 4\nparallelism = 32\n\nload_examples = False\n[webserver]\nexpose_config = False\n",
+		},
+		{
+			"missing key in last section",
+			map[string]map[string]string{"webserver": {"rbac": "True"}},
+			config + "rbac = True\n",
+		},
+		{
+			"missing sections",
+			map[string]map[string]string{"smtp": {"smtp_port": "25"}, "celery": {"worker_concurrency": "4", "pool": "prefork"}},
+			config + "\n[celery]\npool = prefork\nworker_concurrency = 4\n\n[smtp]\nsmtp_port = 25\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(MergeConfig(config, tt.sections)).To(gomega.Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"reflect"
+	"sigs.k8s.io/controller-reconciler/pkg/reconciler"
+	"sigs.k8s.io/controller-reconciler/pkg/reconciler/manager/k8s"
+	"sync"
+)
+
+// Reasons of the events recorded on the AirflowBase and AirflowCluster resources
+const (
+	EventComponentCreated         = "ComponentCreated"
+	EventComponentReady           = "ComponentReady"
+	EventComponentNotReady        = "ComponentNotReady"
+	EventValidationFailed         = "ValidationFailed"
+	EventReconcileFailed          = "ReconcileFailed"
+	EventFinalizationBlocked      = "FinalizationBlocked"
+	EventMemoryStoreProvisioning  = "MemoryStoreProvisioning"
+	EventMemoryStoreReady         = "MemoryStoreReady"
+	EventFernetKeyRotationStarted = "FernetKeyRotationStarted"
+	EventFernetKeyRotationFailed  = "FernetKeyRotationFailed"
+	EventFernetKeyRotated         = "FernetKeyRotated"
+	EventDagRevisionValidated     = "DagRevisionValidated"
+	EventDagValidationFailed      = "DagValidationFailed"
+)
+
+// Reasons of the events recorded on the resources synced into the metadata DB
+const (
+	EventDrift = "Drift"
+)
+
+// Events records the events of a controller on its resources. It remembers
+// the readiness last seen of the StatefulSets of a resource to record when
+// it changes. The readiness first seen after the operator starts is taken
+// as is.
+type Events struct {
+	record.EventRecorder
+	lock  sync.Mutex
+	ready map[types.UID]map[string]bool
+}
+
+// NewEvents returns Events that records with recorder
+func NewEvents(recorder record.EventRecorder) *Events {
+	return &Events{EventRecorder: recorder, ready: map[types.UID]map[string]bool{}}
+}
+
+// Reconciled records the events for the objects a handler reconciled for
+// rsrc: ComponentCreated for those it created and ComponentReady or
+// ComponentNotReady for the StatefulSets whose readiness changed
+func (e *Events) Reconciled(rsrc runtime.Object, reconciled []reconciler.Object) {
+	uid := rsrc.(metav1.Object).GetUID()
+	for _, item := range reconciler.ObjectsByType(reconciled, k8s.Type) {
+		obj := item.Obj.(*k8s.Object).Obj
+		kind := kindOf(obj)
+		// the generic reconciler passes on the expected object for the
+		// ones it created and the observed one, without a lifecycle, for
+		// the others
+		created := item.Lifecycle != ""
+		if created {
+			e.Eventf(rsrc, corev1.EventTypeNormal, EventComponentCreated, "created %s %s", kind, obj.GetName())
+		}
+		sts, ok := obj.(*appsv1.StatefulSet)
+		if !ok {
+			continue
+		}
+		ready := !created && stsReady(sts)
+		if e.changed(uid, kind+"/"+obj.GetName(), ready) {
+			if ready {
+				e.Eventf(rsrc, corev1.EventTypeNormal, EventComponentReady, "%s %s is ready", kind, obj.GetName())
+			} else {
+				e.Eventf(rsrc, corev1.EventTypeWarning, EventComponentNotReady, "%s %s has %d of %d replicas ready",
+					kind, obj.GetName(), sts.Status.ReadyReplicas, *sts.Spec.Replicas)
+			}
+		}
+	}
+}
+
+// Error records that reconciling rsrc failed. errkind is the one passed to
+// the error handler of the generic reconciler.
+func (e *Events) Error(rsrc runtime.Object, err error, errkind string) {
+	if errkind == "validate" {
+		e.Event(rsrc, corev1.EventTypeWarning, EventValidationFailed, err.Error())
+		return
+	}
+	e.Event(rsrc, corev1.EventTypeWarning, EventReconcileFailed, err.Error())
+}
+
+// Forget drops the readiness seen for the objects of rsrc
+func (e *Events) Forget(rsrc metav1.Object) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	delete(e.ready, rsrc.GetUID())
+}
+
+// changed records the readiness of the object key of the resource uid and
+// returns whether a different readiness was seen for it before
+func (e *Events) changed(uid types.UID, key string, ready bool) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	seen, ok := e.ready[uid]
+	if !ok {
+		seen = map[string]bool{}
+		e.ready[uid] = seen
+	}
+	last, ok := seen[key]
+	seen[key] = ready
+	return ok && last != ready
+}
+
+// stsReady matches the readiness the status of the resources reports for a StatefulSet
+func stsReady(sts *appsv1.StatefulSet) bool {
+	return sts.Spec.Replicas != nil &&
+		sts.Status.ReadyReplicas == *sts.Spec.Replicas &&
+		sts.Status.CurrentReplicas == *sts.Spec.Replicas
+}
+
+// kindOf returns the kind of obj. Objects read from the cache have no TypeMeta.
+func kindOf(obj metav1.Object) string {
+	if ro, ok := obj.(runtime.Object); ok {
+		if kind := ro.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+			return kind
+		}
+	}
+	return reflect.TypeOf(obj).Elem().Name()
+}
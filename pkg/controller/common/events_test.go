@@ -0,0 +1,53 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestEventsChanged(t *testing.T) {
+	type seen struct {
+		uid   types.UID
+		key   string
+		ready bool
+		want  bool
+	}
+	tests := []struct {
+		name string
+		seen []seen
+	}{
+		{"first seen", []seen{{"a", "sts", true, false}}},
+		{"same readiness", []seen{{"a", "sts", false, false}, {"a", "sts", false, false}}},
+		{"becomes ready", []seen{{"a", "sts", false, false}, {"a", "sts", true, true}, {"a", "sts", true, false}}},
+		{"becomes not ready", []seen{{"a", "sts", true, false}, {"a", "sts", false, true}}},
+		{"other key", []seen{{"a", "sts", true, false}, {"a", "other", false, false}}},
+		{"other resource", []seen{{"a", "sts", true, false}, {"b", "sts", false, false}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			e := NewEvents(record.NewFakeRecorder(10))
+			for _, s := range tt.seen {
+				g.Expect(e.changed(s.uid, s.key, s.ready)).To(gomega.Equal(s.want))
+			}
+		})
+	}
+}
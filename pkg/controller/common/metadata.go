@@ -0,0 +1,449 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	alpha1 "k8s.io/airflow-operator/pkg/apis/airflow/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	sqlAlchemyConnEnv       = "AIRFLOW__CORE__SQL_ALCHEMY_CONN"
+	metadataContainer       = "metadata"
+	metadataInputEnv        = "METADATA_INPUT"
+	metadataScriptEnv       = "METADATA_SCRIPT"
+	annotationMetadataInput = "airflow.k8s.io/metadata-input"
+)
+
+// metadataCommand runs the script and hands the last line it prints, the
+// result, to the operator through the termination message. A failed script
+// leaves the message empty and the end of its log is used instead.
+const metadataCommand = `python -c "$METADATA_SCRIPT" > /tmp/result && tail -n 1 /tmp/result > /dev/termination-log`
+
+// syncScript upserts or deletes the rows of airflow models listed in the
+// input and prints whether a row with the key of the last one existed and
+// whether it had to change. Fields are set through the model attributes so
+// that encrypted columns are encrypted with the fernet key of the cluster.
+// Fields read from secrets are passed in env vars.
+const syncScript = `
+import json, os
+from airflow import models, settings
+
+norm = lambda v: None if v == '' else v
+session = settings.Session()
+result = {}
+for want in json.loads(os.environ['METADATA_INPUT']):
+    model = getattr(models, want['model'])
+    key = want['key']
+    fields = want.get('fields') or {}
+    for f, env in (want.get('secretFields') or {}).items():
+        if env in os.environ:
+            fields[f] = os.environ[env]
+    found = session.query(model).filter(getattr(model, key) == want['id']).all()
+    result = {'found': len(found) > 0, 'changed': len(found) > 1}
+    for dup in found[1:]:
+        session.delete(dup)
+    if want.get('delete'):
+        for row in found[:1]:
+            session.delete(row)
+            result['changed'] = True
+    else:
+        row = found[0] if found else model()
+        if not found or any(norm(getattr(row, f)) != v for f, v in fields.items()):
+            result['changed'] = True
+            setattr(row, key, want['id'])
+            for f, v in fields.items():
+                setattr(row, f, v)
+            session.add(row)
+session.commit()
+print(json.dumps(result))
+`
+
+// MetadataDB runs python scripts against the metadata DB of an AirflowCluster.
+// A script runs in a Job built from the pod template of the scheduler
+// StatefulSet, with its image, env and config mounts. The Job does not go
+// through the image entrypoint, the DB connection and fernet key come from
+// the AIRFLOW__CORE__* variables of the scheduler env.
+type MetadataDB struct {
+	client client.Client
+}
+
+// MetadataJob is a script to run against the metadata DB of a cluster
+type MetadataJob struct {
+	// Name of the Job, one runs at a time per name
+	Name string
+	// Owner is the resource the Job runs for, the Job is garbage collected with it
+	Owner     metav1.Object
+	OwnerKind string
+	// Namespace and Cluster are the AirflowCluster whose DB the script reads
+	Namespace string
+	Cluster   string
+	Script    string
+	// Input is passed to the script as json in METADATA_INPUT
+	Input interface{}
+	// Env is added to the scheduler env e.g. for values read from secrets
+	Env []corev1.EnvVar
+}
+
+// MetadataRow is a row of an airflow model in the metadata DB
+type MetadataRow struct {
+	// Model is the class in airflow.models e.g. Connection
+	Model string `json:"model"`
+	// Key is the attribute that identifies the row e.g. conn_id
+	Key string `json:"key"`
+	// ID is the value of Key
+	ID string `json:"id"`
+	// Fields are the attributes to set, empty strings are stored as None
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	// SecretFields are the attributes read from secrets by the env var
+	// holding them. They override Fields when the secret has the key.
+	SecretFields map[string]string `json:"secretFields,omitempty"`
+	Delete       bool              `json:"delete,omitempty"`
+	env          []corev1.EnvVar
+}
+
+// FromSecret sets field from the key of a secret. The value reaches the
+// Job through its env and is not part of the Job spec. A missing optional
+// key leaves the value in Fields.
+func (r *MetadataRow) FromSecret(field, secret, key string, optional bool) {
+	if r.SecretFields == nil {
+		r.SecretFields = map[string]string{}
+	}
+	name := "FIELD_" + strings.ToUpper(field)
+	r.SecretFields[field] = name
+	r.env = append(r.env, corev1.EnvVar{Name: name, ValueFrom: &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+			Key:                  key,
+			Optional:             &optional,
+		},
+	}})
+}
+
+type syncResult struct {
+	Found   bool `json:"found"`
+	Changed bool `json:"changed"`
+}
+
+// NewMetadataDB returns a MetadataDB that runs Jobs with c
+func NewMetadataDB(c client.Client) *MetadataDB {
+	return &MetadataDB{client: c}
+}
+
+// Run starts the Job, and once it finished decodes the json object the
+// script printed last into out and deletes the Job. It returns false while
+// the Job runs. A Job started for another input is replaced.
+func (m *MetadataDB) Run(j *MetadataJob, out interface{}) (bool, error) {
+	in, err := json.Marshal(j.Input)
+	if err != nil {
+		return false, err
+	}
+	h := sha256.New()
+	h.Write([]byte(j.Script + "\x00" + string(in)))
+	for _, e := range j.Env {
+		h.Write([]byte("\x00" + e.String()))
+	}
+	digest := hex.EncodeToString(h.Sum(nil))
+
+	job := &batchv1.Job{}
+	err = m.client.Get(context.TODO(), client.ObjectKey{Namespace: j.Namespace, Name: j.Name}, job)
+	if apierrors.IsNotFound(err) {
+		job, err = m.job(j, string(in), digest)
+		if err == nil {
+			err = m.client.Create(context.TODO(), job)
+		}
+		if apierrors.IsAlreadyExists(err) {
+			err = nil
+		}
+		return false, err
+	} else if err != nil {
+		return false, err
+	}
+	if job.DeletionTimestamp != nil {
+		return false, nil
+	}
+	if job.Annotations[annotationMetadataInput] != digest {
+		return false, m.delete(job)
+	}
+	if !JobFinished(job) {
+		return false, nil
+	}
+
+	message, err := m.message(job)
+	if err != nil {
+		return false, err
+	}
+	if err := m.delete(job); err != nil {
+		return false, err
+	}
+	if job.Status.Succeeded == 0 {
+		return false, fmt.Errorf("job %s failed: %s", job.Name, strings.TrimSpace(message))
+	}
+	// airflow may log to stdout while it loads, the result is the last line
+	lines := strings.Split(strings.TrimSpace(message), "\n")
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), out); err != nil {
+		return false, fmt.Errorf("unexpected output from job %s: %v", job.Name, err)
+	}
+	return true, nil
+}
+
+// job builds the Job running the script from the scheduler of the cluster
+func (m *MetadataDB) job(j *MetadataJob, input, digest string) (*batchv1.Job, error) {
+	sts := &appsv1.StatefulSet{}
+	name := RsrcName(j.Cluster, ValueAirflowComponentScheduler, "")
+	if err := m.client.Get(context.TODO(), client.ObjectKey{Namespace: j.Namespace, Name: name}, sts); err != nil {
+		return nil, fmt.Errorf("reading the scheduler of %s: %v", j.Cluster, err)
+	}
+	tmpl := sts.Spec.Template.Spec
+	var scheduler *corev1.Container
+	for i := range tmpl.Containers {
+		if tmpl.Containers[i].Name == ValueAirflowComponentScheduler {
+			scheduler = &tmpl.Containers[i]
+		}
+	}
+	if scheduler == nil || !hasEnv(scheduler, sqlAlchemyConnEnv) {
+		// the statefulset predates the env, it is updated by the next scheduler reconcile
+		return nil, fmt.Errorf("scheduler of %s has no %s", j.Cluster, sqlAlchemyConnEnv)
+	}
+
+	// the claim templates of the statefulset are not volumes a Job can mount
+	volumes := map[string]corev1.Volume{}
+	for _, v := range tmpl.Volumes {
+		volumes[v.Name] = v
+	}
+	mounts := []corev1.VolumeMount{}
+	mounted := []string{}
+	for _, vm := range scheduler.VolumeMounts {
+		if _, ok := volumes[vm.Name]; ok {
+			mounts = append(mounts, vm)
+			mounted = append(mounted, vm.Name)
+		}
+	}
+	sort.Strings(mounted)
+	podVolumes := []corev1.Volume{}
+	for i, name := range mounted {
+		if i == 0 || mounted[i-1] != name {
+			podVolumes = append(podVolumes, volumes[name])
+		}
+	}
+
+	env := append([]corev1.EnvVar{}, scheduler.Env...)
+	env = append(env, j.Env...)
+	env = append(env,
+		corev1.EnvVar{Name: metadataScriptEnv, Value: j.Script},
+		corev1.EnvVar{Name: metadataInputEnv, Value: input},
+	)
+	backoff := int32(0)
+	deadline := int64(300)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        j.Name,
+			Namespace:   j.Namespace,
+			Labels:      map[string]string{"app": "airflow-metadata", "airflow-cluster": j.Cluster},
+			Annotations: map[string]string{annotationMetadataInput: digest},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(j.Owner, alpha1.SchemeGroupVersion.WithKind(j.OwnerKind)),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          &backoff,
+			ActiveDeadlineSeconds: &deadline,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:    corev1.RestartPolicyNever,
+					ImagePullSecrets: tmpl.ImagePullSecrets,
+					SecurityContext:  tmpl.SecurityContext,
+					NodeSelector:     tmpl.NodeSelector,
+					Tolerations:      tmpl.Tolerations,
+					Volumes:          podVolumes,
+					Containers: []corev1.Container{{
+						Name:                     metadataContainer,
+						Image:                    scheduler.Image,
+						ImagePullPolicy:          scheduler.ImagePullPolicy,
+						Command:                  []string{"/bin/sh", "-c", metadataCommand},
+						Env:                      env,
+						EnvFrom:                  scheduler.EnvFrom,
+						VolumeMounts:             mounts,
+						Resources:                scheduler.Resources,
+						TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					}},
+				},
+			},
+		},
+	}, nil
+}
+
+// message returns the termination message of the Job container
+func (m *MetadataDB) message(job *batchv1.Job) (string, error) {
+	pods := &corev1.PodList{}
+	err := m.client.List(context.TODO(), client.InNamespace(job.Namespace).MatchingLabels(map[string]string{"job-name": job.Name}), pods)
+	if err != nil {
+		return "", err
+	}
+	for i := range pods.Items {
+		for _, cs := range pods.Items[i].Status.ContainerStatuses {
+			if cs.Name == metadataContainer && cs.State.Terminated != nil {
+				return cs.State.Terminated.Message, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// delete removes the Job and lets its pods be garbage collected
+func (m *MetadataDB) delete(job *batchv1.Job) error {
+	err := m.client.Delete(context.TODO(), job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// hasEnv returns true if the container sets the env var name
+func hasEnv(c *corev1.Container, name string) bool {
+	for _, e := range c.Env {
+		if e.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// JobFinished returns true once the Job completed or failed
+func JobFinished(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// Sync writes row to the metadata DB of the cluster in the Job name run
+// for owner, a resource of kind, and records it in stts. The row last
+// synced is removed in the same Job if its key changed. version identifies
+// what row was built from. A row that changed in the DB while version
+// stayed the same was edited outside of the operator, Sync overwrites it
+// and returns true as drifted. done is false while the Job runs.
+func (m *MetadataDB) Sync(owner metav1.Object, kind, name, cluster string, row *MetadataRow, version string, stts *alpha1.MetadataSyncStatus) (done, drifted bool, err error) {
+	rows := []*MetadataRow{}
+	if stts.SyncedID != "" && stts.SyncedID != row.ID {
+		rows = append(rows, &MetadataRow{Model: row.Model, Key: row.Key, ID: stts.SyncedID, Delete: true})
+	}
+	rows = append(rows, row)
+	job := &MetadataJob{
+		Name:      name,
+		Owner:     owner,
+		OwnerKind: kind,
+		Namespace: owner.GetNamespace(),
+		Cluster:   cluster,
+		Script:    syncScript,
+		Input:     rows,
+		Env:       row.env,
+	}
+	result := syncResult{}
+	done, err = m.Run(job, &result)
+	if err != nil {
+		stts.SyncJob = ""
+		return false, false, fmt.Errorf("syncing %s %s: %v", row.Model, row.ID, err)
+	}
+	if !done {
+		stts.SyncJob = name
+		return false, false, nil
+	}
+	now := metav1.Now()
+	drifted = result.Found && result.Changed && stts.SyncedVersion == version
+	if drifted {
+		stts.LastDriftTime = &now
+	}
+	stts.SyncJob = ""
+	stts.SyncedVersion = version
+	stts.SyncedID = row.ID
+	stts.LastSyncTime = &now
+	return true, drifted, nil
+}
+
+// Remove deletes the rows with the ids from the metadata DB of the cluster
+// in the Job name run for owner, a resource of kind. Empty ids are skipped.
+// A cluster that is gone or going takes its metadata DB with it. done is
+// false while the Job runs.
+func (m *MetadataDB) Remove(owner metav1.Object, kind, name, cluster, model, key string, ids ...string) (bool, error) {
+	ac := &alpha1.AirflowCluster{}
+	err := m.client.Get(context.TODO(), client.ObjectKey{Namespace: owner.GetNamespace(), Name: cluster}, ac)
+	if apierrors.IsNotFound(err) || (err == nil && ac.DeletionTimestamp != nil) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	rows := []*MetadataRow{}
+	done := map[string]bool{"": true}
+	for _, id := range ids {
+		if done[id] {
+			continue
+		}
+		done[id] = true
+		rows = append(rows, &MetadataRow{Model: model, Key: key, ID: id, Delete: true})
+	}
+	if len(rows) == 0 {
+		return true, nil
+	}
+	job := &MetadataJob{
+		Name:      name,
+		Owner:     owner,
+		OwnerKind: kind,
+		Namespace: owner.GetNamespace(),
+		Cluster:   cluster,
+		Script:    syncScript,
+		Input:     rows,
+	}
+	return m.Run(job, &syncResult{})
+}
+
+// SyncDue returns true when a resource has to be written to the metadata
+// DB: its sync Job runs, it changed since it was last synced or the drift
+// check interval passed
+func SyncDue(stts *alpha1.MetadataSyncStatus, version string, interval time.Duration) bool {
+	return stts.SyncJob != "" || stts.SyncedVersion != version ||
+		stts.LastSyncTime == nil || time.Since(stts.LastSyncTime.Time) >= interval
+}
+
+// SyncVersion returns a digest of spec and the versions of the secrets it
+// refers to. It changes whenever the row built from them may change.
+func SyncVersion(spec interface{}, secrets ...*corev1.Secret) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write(data)
+	for _, s := range secrets {
+		h.Write([]byte("\x00" + s.Name + "/" + s.ResourceVersion))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,220 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	alpha1 "k8s.io/airflow-operator/pkg/apis/airflow/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newScheduler(env ...corev1.EnvVar) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo-scheduler", Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{{Name: "config"}},
+					Containers: []corev1.Container{
+						{
+							Name:  "scheduler",
+							Image: "airflow:1.10.2",
+							Env:   env,
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "config", MountPath: "/usr/local/airflow/airflow.cfg"},
+								{Name: "dags-data", MountPath: "/usr/local/airflow/dags"},
+							},
+						},
+						{Name: "metrics", Image: "statsd-exporter"},
+					},
+				},
+			},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "dags-data"}}},
+		},
+	}
+}
+
+func newConnection() *alpha1.AirflowConnection {
+	return &alpha1.AirflowConnection{
+		ObjectMeta: metav1.ObjectMeta{Name: "conn", Namespace: "default", UID: "uid"},
+	}
+}
+
+// jobPods lists the pods of the Jobs, the fake client cannot list by label
+type jobPods struct {
+	client.Client
+	pods corev1.PodList
+}
+
+func (c *jobPods) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	c.pods.DeepCopyInto(list.(*corev1.PodList))
+	return nil
+}
+
+// finishJob marks the Job done and adds its pod with the termination message
+func finishJob(g *gomega.GomegaWithT, c *jobPods, name string, succeeded bool, message string) {
+	job := &batchv1.Job{}
+	g.Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: name}, job)).To(gomega.Succeed())
+	cond := batchv1.JobFailed
+	if succeeded {
+		cond = batchv1.JobComplete
+		job.Status.Succeeded = 1
+	}
+	job.Status.Conditions = []batchv1.JobCondition{{Type: cond, Status: corev1.ConditionTrue}}
+	g.Expect(c.Update(context.TODO(), job)).To(gomega.Succeed())
+	c.pods.Items = append(c.pods.Items, corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name + "-abcde", Namespace: "default", Labels: map[string]string{"job-name": name}},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name:  metadataContainer,
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: message}},
+		}}},
+	})
+}
+
+func TestMetadataJob(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	conn := corev1.EnvVar{Name: sqlAlchemyConnEnv, Value: "mysql://foo"}
+	db := NewMetadataDB(fake.NewFakeClient(newScheduler(conn)))
+	row := &MetadataRow{Model: "Connection", Key: "conn_id", ID: "db", Fields: map[string]interface{}{"password": ""}}
+	row.FromSecret("password", "db-secret", "password", true)
+	j := &MetadataJob{Name: "conn-sync", Owner: newConnection(), OwnerKind: "AirflowConnection",
+		Namespace: "default", Cluster: "foo", Script: syncScript, Input: []*MetadataRow{row}, Env: row.env}
+
+	job, err := db.job(j, "[]", "digest")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(job.OwnerReferences).To(gomega.HaveLen(1))
+	g.Expect(job.OwnerReferences[0].Kind).To(gomega.Equal("AirflowConnection"))
+	g.Expect(*job.Spec.BackoffLimit).To(gomega.BeZero())
+	pod := job.Spec.Template.Spec
+	g.Expect(pod.RestartPolicy).To(gomega.Equal(corev1.RestartPolicyNever))
+	g.Expect(pod.Containers).To(gomega.HaveLen(1))
+	c := pod.Containers[0]
+	g.Expect(c.Image).To(gomega.Equal("airflow:1.10.2"))
+	// the claim template of the statefulset is not mounted
+	g.Expect(pod.Volumes).To(gomega.Equal([]corev1.Volume{{Name: "config"}}))
+	g.Expect(c.VolumeMounts).To(gomega.HaveLen(1))
+	g.Expect(c.Env).To(gomega.ContainElement(conn))
+	g.Expect(c.Env).To(gomega.ContainElement(corev1.EnvVar{Name: metadataInputEnv, Value: "[]"}))
+	// the password is read from the secret by the pod
+	g.Expect(c.Env).To(gomega.ContainElement(row.env[0]))
+	g.Expect(row.SecretFields).To(gomega.Equal(map[string]string{"password": "FIELD_PASSWORD"}))
+
+	_, err = NewMetadataDB(fake.NewFakeClient(newScheduler())).job(j, "[]", "digest")
+	g.Expect(err).To(gomega.HaveOccurred())
+	_, err = NewMetadataDB(fake.NewFakeClient()).job(j, "[]", "digest")
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestMetadataRun(t *testing.T) {
+	tests := []struct {
+		name      string
+		succeeded bool
+		message   string
+		want      syncResult
+		wantErr   bool
+	}{
+		{"succeeded", true, "loading dags\n{\"found\": true, \"changed\": true}\n", syncResult{Found: true, Changed: true}, false},
+		{"failed", false, "Traceback: OperationalError", syncResult{}, true},
+		{"unexpected output", true, "done", syncResult{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			c := &jobPods{Client: fake.NewFakeClient(newScheduler(corev1.EnvVar{Name: sqlAlchemyConnEnv, Value: "mysql://foo"}))}
+			db := NewMetadataDB(c)
+			j := &MetadataJob{Name: "conn-sync", Owner: newConnection(), OwnerKind: "AirflowConnection",
+				Namespace: "default", Cluster: "foo", Script: syncScript, Input: []string{"a"}}
+
+			got := syncResult{}
+			done, err := db.Run(j, &got)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(done).To(gomega.BeFalse())
+			// the running Job is polled
+			done, err = db.Run(j, &got)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(done).To(gomega.BeFalse())
+
+			finishJob(g, c, j.Name, tt.succeeded, tt.message)
+			done, err = db.Run(j, &got)
+			if tt.wantErr {
+				g.Expect(err).To(gomega.HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(done).To(gomega.BeTrue())
+			}
+			g.Expect(got).To(gomega.Equal(tt.want))
+			err = c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: j.Name}, &batchv1.Job{})
+			g.Expect(apierrors.IsNotFound(err)).To(gomega.BeTrue())
+		})
+	}
+}
+
+func TestMetadataRunInputChanged(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	c := fake.NewFakeClient(newScheduler(corev1.EnvVar{Name: sqlAlchemyConnEnv, Value: "mysql://foo"}))
+	db := NewMetadataDB(c)
+	j := &MetadataJob{Name: "conn-sync", Owner: newConnection(), OwnerKind: "AirflowConnection",
+		Namespace: "default", Cluster: "foo", Script: syncScript, Input: "a"}
+	done, err := db.Run(j, &syncResult{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(done).To(gomega.BeFalse())
+
+	// a Job started for an older input is replaced
+	j.Input = "b"
+	done, err = db.Run(j, &syncResult{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(done).To(gomega.BeFalse())
+	err = c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: j.Name}, &batchv1.Job{})
+	g.Expect(apierrors.IsNotFound(err)).To(gomega.BeTrue())
+	_, err = db.Run(j, &syncResult{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	job := &batchv1.Job{}
+	g.Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: j.Name}, job)).To(gomega.Succeed())
+	g.Expect(job.Spec.Template.Spec.Containers[0].Env).To(gomega.ContainElement(corev1.EnvVar{Name: metadataInputEnv, Value: `"b"`}))
+}
+
+func TestSyncDue(t *testing.T) {
+	recent := metav1.NewTime(time.Now().Add(-time.Minute))
+	old := metav1.NewTime(time.Now().Add(-time.Hour))
+	tests := []struct {
+		name string
+		stts alpha1.MetadataSyncStatus
+		want bool
+	}{
+		{"never synced", alpha1.MetadataSyncStatus{}, true},
+		{"synced", alpha1.MetadataSyncStatus{SyncedVersion: "v1", LastSyncTime: &recent}, false},
+		{"changed", alpha1.MetadataSyncStatus{SyncedVersion: "v0", LastSyncTime: &recent}, true},
+		{"drift check", alpha1.MetadataSyncStatus{SyncedVersion: "v1", LastSyncTime: &old}, true},
+		{"job running", alpha1.MetadataSyncStatus{SyncedVersion: "v1", LastSyncTime: &recent, SyncJob: "conn-sync"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(SyncDue(&tt.stts, "v1", 5*time.Minute)).To(gomega.Equal(tt.want))
+		})
+	}
+}
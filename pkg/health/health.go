@@ -0,0 +1,92 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package health serves the liveness and readiness endpoints of the operator
+// pod. The manager only starts its runnables on the leader, the endpoints are
+// served outside of it so that standby replicas answer the probes too.
+package health
+
+import (
+	"fmt"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"net"
+	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sync/atomic"
+	"time"
+)
+
+// Server serves /healthz and /readyz
+type Server struct {
+	Addr   string
+	Config *rest.Config
+	leader int32
+}
+
+// Leading returns a runnable for the manager that marks the replica as the
+// leader while it runs. Runnables start once the caches synced.
+func (s *Server) Leading() manager.Runnable {
+	return manager.RunnableFunc(func(stop <-chan struct{}) error {
+		atomic.StoreInt32(&s.leader, 1)
+		<-stop
+		atomic.StoreInt32(&s.leader, 0)
+		return nil
+	})
+}
+
+// role is what the replica does, the leader reconciles and the others wait
+// for the lease
+func (s *Server) role() string {
+	if atomic.LoadInt32(&s.leader) == 1 {
+		return "leader"
+	}
+	return "standby"
+}
+
+// Start serves the endpoints until stop is closed. /healthz answers as long
+// as the process runs, /readyz once the API server can be reached.
+func (s *Server) Start(stop <-chan struct{}) error {
+	cfg := rest.CopyConfig(s.Config)
+	cfg.Timeout = 5 * time.Second
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, "ok", s.role())
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		if _, err := dc.ServerVersion(); err != nil {
+			http.Error(w, "api server: "+err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok", s.role())
+	})
+	ln, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Handler: mux}
+	go func() {
+		<-stop
+		srv.Close()
+	}()
+	if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
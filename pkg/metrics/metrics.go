@@ -0,0 +1,292 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics serves prometheus metrics about the operator: how its
+// reconciles go and what state the resources it manages are in.
+package metrics
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	alpha1 "k8s.io/airflow-operator/pkg/apis/airflow/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"net"
+	"net/http"
+	"sigs.k8s.io/controller-reconciler/pkg/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sync"
+	"time"
+)
+
+const namespace = "airflow_operator"
+
+// Registry holds the operator metrics. It is served instead of the default
+// registry so that only metrics registered here are exposed.
+var Registry = prometheus.NewRegistry()
+
+var (
+	reconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconcile_total",
+		Help:      "Reconciles per resource.",
+	}, []string{"kind", "namespace", "name"})
+	reconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconcile_errors_total",
+		Help:      "Failed reconciles per resource and the stage they failed in: validate, reconcile or update.",
+	}, []string{"kind", "namespace", "name", "stage"})
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "reconcile_duration_seconds",
+		Help:      "Time a reconcile of a resource took.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"kind", "namespace", "name"})
+	handlerTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "handler_reconcile_total",
+		Help:      "Reconciles per handler e.g. UI, Scheduler, MySQL.",
+	}, []string{"kind", "handler"})
+	handlerErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "handler_reconcile_errors_total",
+		Help:      "Failed reconciles per handler.",
+	}, []string{"kind", "handler"})
+	handlerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "handler_reconcile_duration_seconds",
+		Help:      "Time a handler took to reconcile its objects.",
+		Buckets:   []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"kind", "handler"})
+	leader = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "leader",
+		Help:      "1 when the replica is the leader and reconciles, 0 while it waits for the lease.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		reconcileTotal, reconcileErrors, reconcileDuration,
+		handlerTotal, handlerErrors, handlerDuration, leader,
+	)
+}
+
+// handlers run one after the other in a reconcile. marks holds when the
+// reconcile of a resource started or its last handler finished. forgotten
+// holds the finalized resources whose series are not recorded anymore.
+var (
+	marksLock sync.Mutex
+	marks     = map[string]time.Time{}
+	forgotten = map[string]bool{}
+)
+
+func markKey(kind string, nn types.NamespacedName) string {
+	return kind + "/" + nn.String()
+}
+
+// mark sets the mark of a resource to now and returns the previous one
+func mark(key string) (time.Time, bool) {
+	marksLock.Lock()
+	defer marksLock.Unlock()
+	last, ok := marks[key]
+	marks[key] = time.Now()
+	return last, ok
+}
+
+// Reconciler counts and times the reconciles of a kind of resource
+type Reconciler struct {
+	reconcile.Reconciler
+	kind string
+}
+
+// NewReconciler returns a Reconciler that instruments r
+func NewReconciler(kind string, r reconcile.Reconciler) *Reconciler {
+	return &Reconciler{Reconciler: r, kind: kind}
+}
+
+// Reconcile the resource with the instrumented reconciler. Errors of the
+// handlers are recorded by the error handler of the controller, err here
+// means the status could not be written.
+func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	key := markKey(r.kind, request.NamespacedName)
+	mark(key)
+	start := time.Now()
+	result, err := r.Reconciler.Reconcile(request)
+	marksLock.Lock()
+	delete(marks, key)
+	gone := forgotten[key]
+	marksLock.Unlock()
+
+	ns, name := request.Namespace, request.Name
+	if gone {
+		reconcileTotal.DeleteLabelValues(r.kind, ns, name)
+		reconcileDuration.DeleteLabelValues(r.kind, ns, name)
+		for _, stage := range []string{"validate", "reconcile", "update"} {
+			reconcileErrors.DeleteLabelValues(r.kind, ns, name, stage)
+		}
+		return result, err
+	}
+	reconcileTotal.WithLabelValues(r.kind, ns, name).Inc()
+	reconcileDuration.WithLabelValues(r.kind, ns, name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		reconcileErrors.WithLabelValues(r.kind, ns, name, "update").Inc()
+	}
+	return result, err
+}
+
+// ReconcileError records that reconciling the resource failed. stage is the
+// error kind passed to the error handler of the generic reconciler, an
+// empty one is a handler error.
+func ReconcileError(kind string, obj metav1.Object, stage string) {
+	if stage == "" {
+		stage = "reconcile"
+	}
+	reconcileErrors.WithLabelValues(kind, obj.GetNamespace(), obj.GetName(), stage).Inc()
+}
+
+// Forget drops the series of a resource once it is finalized. Reconciles of
+// the deleted resource are not recorded until it is created again.
+func Forget(kind string, obj metav1.Object) {
+	nn := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	marksLock.Lock()
+	defer marksLock.Unlock()
+	forgotten[markKey(kind, nn)] = true
+}
+
+// HandlerDone records that handler finished reconciling its objects of the
+// resource with err. It is called from the UpdateStatus of the handler.
+func HandlerDone(kind string, obj metav1.Object, handler string, err error) {
+	nn := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	key := markKey(kind, nn)
+	marksLock.Lock()
+	delete(forgotten, key)
+	marksLock.Unlock()
+	last, ok := mark(key)
+	handlerTotal.WithLabelValues(kind, handler).Inc()
+	if ok {
+		handlerDuration.WithLabelValues(kind, handler).Observe(time.Since(last).Seconds())
+	}
+	if err != nil {
+		handlerErrors.WithLabelValues(kind, handler).Inc()
+	}
+}
+
+var (
+	clustersDesc = prometheus.NewDesc(namespace+"_clusters",
+		"AirflowClusters managed by the operator per executor.",
+		[]string{"executor"}, nil)
+	componentReadyDesc = prometheus.NewDesc(namespace+"_component_ready",
+		"1 when a component object of a resource is ready, 0 while it is in progress.",
+		[]string{"kind", "namespace", "name", "component"}, nil)
+)
+
+// statusCollector reads the cluster counts and component readiness from
+// the resources in the caches at scrape time
+type statusCollector struct {
+	clients []client.Client
+}
+
+// RegisterStatusCollector adds the metrics read from the status of the
+// AirflowBase and AirflowCluster resources to the Registry. There is a
+// client per manager when the operator watches several namespaces.
+func RegisterStatusCollector(clients ...client.Client) {
+	Registry.MustRegister(&statusCollector{clients: clients})
+}
+
+// Describe the metrics of the collector
+func (s *statusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- clustersDesc
+	ch <- componentReadyDesc
+}
+
+// Collect the metrics from the cached resources
+func (s *statusCollector) Collect(ch chan<- prometheus.Metric) {
+	log := logf.Log.WithName("metrics")
+	executors := map[string]int{}
+	for _, cl := range s.clients {
+		bases := &alpha1.AirflowBaseList{}
+		if err := cl.List(context.TODO(), &client.ListOptions{}, bases); err != nil {
+			log.Error(err, "listing AirflowBases")
+		}
+		for _, b := range bases.Items {
+			collectComponents(ch, "AirflowBase", &b, &b.Status.ComponentMeta)
+		}
+		clusters := &alpha1.AirflowClusterList{}
+		if err := cl.List(context.TODO(), &client.ListOptions{}, clusters); err != nil {
+			log.Error(err, "listing AirflowClusters")
+		}
+		for _, c := range clusters.Items {
+			executors[c.Spec.Executor]++
+			collectComponents(ch, "AirflowCluster", &c, &c.Status.ComponentMeta)
+		}
+	}
+	for executor, count := range executors {
+		ch <- prometheus.MustNewConstMetric(clustersDesc, prometheus.GaugeValue, float64(count), executor)
+	}
+}
+
+func collectComponents(ch chan<- prometheus.Metric, kind string, obj metav1.Object, meta *status.ComponentMeta) {
+	for _, o := range meta.Objects {
+		ready := 0.0
+		if o.Status == status.StatusReady {
+			ready = 1
+		}
+		ch <- prometheus.MustNewConstMetric(componentReadyDesc, prometheus.GaugeValue, ready,
+			kind, obj.GetNamespace(), obj.GetName(), o.Kind+"/"+o.Name)
+	}
+}
+
+// Leading returns a runnable for the manager that sets the leader gauge
+// while it runs. Runnables start once the replica leads.
+func Leading() manager.Runnable {
+	return manager.RunnableFunc(func(stop <-chan struct{}) error {
+		leader.Set(1)
+		<-stop
+		leader.Set(0)
+		return nil
+	})
+}
+
+// Server serves the Registry on /metrics. It runs next to the manager and
+// not in it so that the replicas waiting for the lease are scraped too.
+type Server struct {
+	Addr string
+}
+
+// Start serves the metrics until stop is closed
+func (s *Server) Start(stop <-chan struct{}) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+	ln, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Handler: mux}
+	go func() {
+		<-stop
+		srv.Close()
+	}()
+	if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
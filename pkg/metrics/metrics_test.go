@@ -0,0 +1,149 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reconcilerFunc reconciles a request by calling a func
+type reconcilerFunc func(reconcile.Request) (reconcile.Result, error)
+
+func (f reconcilerFunc) Reconcile(r reconcile.Request) (reconcile.Result, error) { return f(r) }
+
+// observations returns the samples of a histogram series
+func observations(h *prometheus.HistogramVec, labels ...string) uint64 {
+	m := &dto.Metric{}
+	h.WithLabelValues(labels...).(prometheus.Histogram).Write(m)
+	return m.GetHistogram().GetSampleCount()
+}
+
+// series returns the number of series of a collector
+func series(c prometheus.Collector) int {
+	ch := make(chan prometheus.Metric, 100)
+	c.Collect(ch)
+	close(ch)
+	return len(ch)
+}
+
+func TestMark(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	key := markKey("Test", types.NamespacedName{Namespace: "default", Name: "mark"})
+	_, ok := mark(key)
+	g.Expect(ok).To(gomega.BeFalse())
+	first := marks[key]
+	time.Sleep(time.Millisecond)
+	last, ok := mark(key)
+	g.Expect(ok).To(gomega.BeTrue())
+	g.Expect(last).To(gomega.Equal(first))
+	g.Expect(marks[key].After(first)).To(gomega.BeTrue())
+}
+
+func TestHandlerDone(t *testing.T) {
+	tests := []struct {
+		name      string
+		reconcile bool
+		handlers  []error
+		timed     uint64
+		errors    float64
+	}{
+		{"outside a reconcile", false, []error{nil}, 0, 0},
+		{"first handler", true, []error{nil}, 1, 0},
+		{"each handler", true, []error{nil, nil, nil}, 3, 0},
+		{"handler errors", true, []error{errors.New("boom"), nil, errors.New("boom")}, 3, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			kind := "HandlerDone " + tt.name
+			obj := &metav1.ObjectMeta{Namespace: "default", Name: "af"}
+			r := NewReconciler(kind, reconcilerFunc(func(reconcile.Request) (reconcile.Result, error) {
+				for _, err := range tt.handlers {
+					HandlerDone(kind, obj, "Scheduler", err)
+				}
+				return reconcile.Result{}, nil
+			}))
+			if tt.reconcile {
+				r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "af"}})
+			} else {
+				for _, err := range tt.handlers {
+					HandlerDone(kind, obj, "Scheduler", err)
+				}
+			}
+			g.Expect(testutil.ToFloat64(handlerTotal.WithLabelValues(kind, "Scheduler"))).To(gomega.Equal(float64(len(tt.handlers))))
+			g.Expect(observations(handlerDuration, kind, "Scheduler")).To(gomega.Equal(tt.timed))
+			g.Expect(testutil.ToFloat64(handlerErrors.WithLabelValues(kind, "Scheduler"))).To(gomega.Equal(tt.errors))
+		})
+	}
+}
+
+func TestReconcilerForget(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	kind := "Forget"
+	obj := &metav1.ObjectMeta{Namespace: "default", Name: "af"}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "af"}}
+	finalize := false
+	r := NewReconciler(kind, reconcilerFunc(func(reconcile.Request) (reconcile.Result, error) {
+		if finalize {
+			Forget(kind, obj)
+			return reconcile.Result{}, nil
+		}
+		HandlerDone(kind, obj, "Scheduler", nil)
+		return reconcile.Result{}, errors.New("conflict")
+	}))
+	before := series(reconcileTotal)
+
+	r.Reconcile(request)
+	g.Expect(testutil.ToFloat64(reconcileTotal.WithLabelValues(kind, "default", "af"))).To(gomega.Equal(1.0))
+	g.Expect(testutil.ToFloat64(reconcileErrors.WithLabelValues(kind, "default", "af", "update"))).To(gomega.Equal(1.0))
+
+	finalize = true
+	r.Reconcile(request)
+	g.Expect(series(reconcileTotal)).To(gomega.Equal(before))
+	g.Expect(reconcileErrors.DeleteLabelValues(kind, "default", "af", "update")).To(gomega.BeFalse())
+	g.Expect(reconcileDuration.DeleteLabelValues(kind, "default", "af")).To(gomega.BeFalse())
+
+	// a reconcile of the deleted resource does not record it again
+	r.Reconcile(request)
+	g.Expect(series(reconcileTotal)).To(gomega.Equal(before))
+
+	// until it is created again
+	finalize = false
+	r.Reconcile(request)
+	g.Expect(testutil.ToFloat64(reconcileTotal.WithLabelValues(kind, "default", "af"))).To(gomega.Equal(1.0))
+}
+
+func TestLeading(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	g.Expect(testutil.ToFloat64(leader)).To(gomega.Equal(0.0))
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() { done <- Leading().Start(stop) }()
+	g.Eventually(func() float64 { return testutil.ToFloat64(leader) }).Should(gomega.Equal(1.0))
+	close(stop)
+	g.Expect(<-done).NotTo(gomega.HaveOccurred())
+	g.Expect(testutil.ToFloat64(leader)).To(gomega.Equal(0.0))
+}